@@ -0,0 +1,286 @@
+// Package deferclose defines an Analyzer that reports ignored errors
+// from deferred Close, Flush and Sync calls and from Write calls on io.Writer values.
+//
+// Errors from closing writable resources are often the only signal of failed writes
+// (e.g. buffered data could not be flushed to disk), but 'defer f.Close()'
+// silently drops them.
+//
+// Suggested fixes:
+//  * if function has 'defer errf.IfError()...' handler before the statement:
+//      defer w.Close()  ->  defer errf.CheckDeferErr(w.Close)
+//      w.Write(buf)     ->  errf.CheckDiscard(w.Write(buf))
+//  * otherwise, if function returns an error:
+//      defer w.Close()  ->  defer errf.IfErrorAssignTo(&err, w.Close)
+//    (error result is named 'err', if needed).
+package deferclose
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/serhiy-t/errf/internal/analysisutil"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports ignored errors from deferred Close, Flush and Sync calls
+// and from Write calls on io.Writer values.
+var Analyzer = &analysis.Analyzer{
+	Name:     "deferclose",
+	Doc:      "report ignored errors from deferred Close/Flush/Sync and Write calls on io.Writer values",
+	URL:      "https://pkg.go.dev/github.com/serhiy-t/errf/analysis/deferclose",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+var deferredMethods = map[string]bool{
+	"Close": true,
+	"Flush": true,
+	"Sync":  true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{(*ast.DeferStmt)(nil), (*ast.ExprStmt)(nil)}
+	inspect.WithStack(nodeFilter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		fn := analysisutil.EnclosingFunc(stack)
+		if fn == nil {
+			return true
+		}
+		switch stmt := node.(type) {
+		case *ast.DeferStmt:
+			checkDefer(pass, stmt, fn, stack)
+		case *ast.ExprStmt:
+			checkWrite(pass, stmt, fn, stack)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// writerMethod returns receiver expression, if call is a method call
+// on io.Writer value without arguments returning error (e.g. w.Close()).
+func writerMethod(pass *analysis.Pass, call *ast.CallExpr, names map[string]bool) ast.Expr {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !names[selector.Sel.Name] {
+		return nil
+	}
+	if !isWriter(pass.TypesInfo.TypeOf(selector.X)) {
+		return nil
+	}
+	return selector.X
+}
+
+var writerType = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "Write", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "p", types.NewSlice(types.Typ[types.Byte]))),
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "n", types.Typ[types.Int]),
+			types.NewVar(token.NoPos, nil, "err", types.Universe.Lookup("error").Type())),
+		false)),
+}, nil).Complete()
+
+// neverFail lists writers, which are documented to never return errors.
+var neverFail = map[string]bool{
+	"*bytes.Buffer":    true,
+	"*strings.Builder": true,
+	"hash.Hash":        true,
+	"hash.Hash32":      true,
+	"hash.Hash64":      true,
+}
+
+func isWriter(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	typeName := types.TypeString(t, nil)
+	if neverFail[typeName] || neverFail["*"+typeName] {
+		return false
+	}
+	return types.Implements(t, writerType) || types.Implements(types.NewPointer(t), writerType)
+}
+
+func returnsError(pass *analysis.Pass, call *ast.CallExpr) bool {
+	signature, ok := pass.TypesInfo.TypeOf(call.Fun).(*types.Signature)
+	if !ok {
+		return false
+	}
+	results := signature.Results()
+	return results.Len() > 0 && analysisutil.IsErrorType(results.At(results.Len()-1).Type())
+}
+
+func checkDefer(pass *analysis.Pass, stmt *ast.DeferStmt, fn *analysisutil.Function, stack []ast.Node) {
+	call := stmt.Call
+	receiver := writerMethod(pass, call, deferredMethods)
+	if receiver == nil || len(call.Args) != 0 || !returnsError(pass, call) {
+		return
+	}
+	method := analysisutil.Render(pass.Fset, call.Fun)
+	errfName, hasImport := analysisutil.ErrfImportName(analysisutil.FileOf(stack))
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     stmt.Pos(),
+		End:     stmt.End(),
+		Message: fmt.Sprintf("error from deferred %s() is ignored", method),
+	}
+	if analysisutil.IfErrorScope(pass.TypesInfo, fn, stmt.Pos()) != nil {
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Use errf.CheckDeferErr",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     stmt.Pos(),
+				End:     stmt.End(),
+				NewText: []byte(fmt.Sprintf("defer %s.CheckDeferErr(%s)", errfName, method)),
+			}},
+		}}
+	} else if errName, edits, ok := errorResult(pass, fn); ok {
+		edits = append(edits, analysis.TextEdit{
+			Pos:     stmt.Pos(),
+			End:     stmt.End(),
+			NewText: []byte(fmt.Sprintf("defer %s.IfErrorAssignTo(&%s, %s)", errfName, errName, method)),
+		})
+		if !hasImport {
+			edits = append(edits, addImportEdit(stack))
+		}
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message:   "Use errf.IfErrorAssignTo",
+			TextEdits: edits,
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+func checkWrite(pass *analysis.Pass, stmt *ast.ExprStmt, fn *analysisutil.Function, stack []ast.Node) {
+	call, ok := stmt.X.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	receiver := writerMethod(pass, call, map[string]bool{"Write": true})
+	if receiver == nil || !returnsError(pass, call) {
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     stmt.Pos(),
+		End:     stmt.End(),
+		Message: fmt.Sprintf("error from %s is ignored", analysisutil.Render(pass.Fset, call.Fun)),
+	}
+	if analysisutil.IfErrorScope(pass.TypesInfo, fn, stmt.Pos()) != nil {
+		errfName, _ := analysisutil.ErrfImportName(analysisutil.FileOf(stack))
+		diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+			Message: "Use errf.CheckDiscard",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     stmt.Pos(),
+				End:     stmt.End(),
+				NewText: []byte(fmt.Sprintf("%s.CheckDiscard(%s)", errfName, analysisutil.Render(pass.Fset, call))),
+			}},
+		}}
+	}
+	pass.Report(diagnostic)
+}
+
+// errorResult returns name of function error result and edits,
+// which name function results, if they are unnamed.
+func errorResult(pass *analysis.Pass, fn *analysisutil.Function) (string, []analysis.TextEdit, bool) {
+	results := fn.Type.Results
+	if results == nil || len(results.List) == 0 {
+		return "", nil, false
+	}
+	lastField := results.List[len(results.List)-1]
+	if !analysisutil.IsErrorType(pass.TypesInfo.TypeOf(lastField.Type)) {
+		return "", nil, false
+	}
+	if len(lastField.Names) > 0 {
+		name := lastField.Names[len(lastField.Names)-1].Name
+		return name, nil, name != "_"
+	}
+
+	// Results are unnamed: rename to (_ T1, ..., err error).
+	if declaresErr(pass, fn) {
+		return "", nil, false
+	}
+	var buf bytes.Buffer
+	buf.WriteString("(")
+	for i, field := range results.List {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		if i == len(results.List)-1 {
+			buf.WriteString("err ")
+		} else {
+			buf.WriteString("_ ")
+		}
+		buf.WriteString(analysisutil.Render(pass.Fset, field.Type))
+	}
+	buf.WriteString(")")
+	return "err", []analysis.TextEdit{{Pos: results.Pos(), End: results.End(), NewText: buf.Bytes()}}, true
+}
+
+// declaresErr reports whether 'err' name can't be used for function result,
+// because it is a parameter or it is declared in function body block.
+// Declarations like 'file, err := ...' are allowed: they would reuse the result.
+func declaresErr(pass *analysis.Pass, fn *analysisutil.Function) bool {
+	for _, field := range fn.Type.Params.List {
+		for _, name := range field.Names {
+			if name.Name == "err" {
+				return true
+			}
+		}
+	}
+	for _, stmt := range fn.Body.List {
+		switch s := stmt.(type) {
+		case *ast.DeclStmt:
+			if genDecl, ok := s.Decl.(*ast.GenDecl); ok {
+				for _, spec := range genDecl.Specs {
+					if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+						for _, name := range valueSpec.Names {
+							if name.Name == "err" {
+								return true
+							}
+						}
+					}
+				}
+			}
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				continue
+			}
+			definesErr, definesOther := false, false
+			for _, lhs := range s.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && pass.TypesInfo.Defs[ident] != nil {
+					if ident.Name == "err" {
+						definesErr = true
+					} else {
+						definesOther = true
+					}
+				}
+			}
+			if definesErr && !definesOther {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func addImportEdit(stack []ast.Node) analysis.TextEdit {
+	file := analysisutil.FileOf(stack)
+	importLine := strconv.Quote(analysisutil.ErrfPath)
+	for _, decl := range file.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			if genDecl.Rparen.IsValid() {
+				return analysis.TextEdit{Pos: genDecl.Rparen, End: genDecl.Rparen, NewText: []byte("\n" + importLine + "\n")}
+			}
+			return analysis.TextEdit{Pos: genDecl.End(), End: genDecl.End(), NewText: []byte("\nimport " + importLine)}
+		}
+	}
+	return analysis.TextEdit{Pos: file.Name.End(), End: file.Name.End(), NewText: []byte("\n\nimport " + importLine)}
+}
@@ -0,0 +1,79 @@
+package a
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+
+	"github.com/serhiy-t/errf"
+)
+
+func withScope(filename string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // want `error from deferred file.Close\(\) is ignored`
+
+	writer := bufio.NewWriter(file)
+	defer writer.Flush() // want `error from deferred writer.Flush\(\) is ignored`
+
+	writer.Write([]byte("data")) // want `error from writer.Write is ignored`
+	return nil
+}
+
+func unnamedResults(filename string) (int, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Sync() // want `error from deferred file.Sync\(\) is ignored`
+	return 0, nil
+}
+
+func namedResult(filename string) (result error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // want `error from deferred file.Close\(\) is ignored`
+	return nil
+}
+
+func scopeAfterDefer(filename string) (err error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // want `error from deferred file.Close\(\) is ignored`
+	file.Write(nil)    // want `error from file.Write is ignored`
+
+	defer errf.IfError().ThenAssignTo(&err)
+	return nil
+}
+
+func noErrorResult(filename string) {
+	file, _ := os.Create(filename)
+	defer file.Close() // want `error from deferred file.Close\(\) is ignored`
+	file.Write(nil)    // want `error from file.Write is ignored`
+}
+
+func notWriters(filename string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	var buf bytes.Buffer
+	buf.Write([]byte("data"))
+
+	reader := bytes.NewReader(nil)
+	_ = reader
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer errf.CheckDeferErr(file.Close)
+	_, _ = file.Write(nil)
+	return nil
+}
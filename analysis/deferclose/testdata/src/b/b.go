@@ -0,0 +1,14 @@
+package b
+
+import (
+	"os"
+)
+
+func create(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close() // want `error from deferred file.Close\(\) is ignored`
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package errf is a minimal errflow API stub for analyzer tests.
+package errf
+
+type IfErrorHandler struct{}
+
+func IfError() *IfErrorHandler { return &IfErrorHandler{} }
+
+func (c *IfErrorHandler) ThenAssignTo(outErr *error) {}
+
+type CheckResult struct {
+	IfOkReturnNil error
+}
+
+func CheckDeferErr(closeFn func() error) CheckResult { return CheckResult{} }
+
+func CheckDiscard(_ interface{}, err error) CheckResult { return CheckResult{} }
+
+func IfErrorAssignTo(outErr *error, closeFn func() error) {}
@@ -0,0 +1,254 @@
+// Package modernize defines an Analyzer that reports errflow usages,
+// which are not recommended by errflow documentation, and suggests replacements.
+//
+// Reported usages:
+//  * CheckAny with a type assertion to the function result type:
+//      n := errf.CheckAny(strconv.Atoi(s)).(int)  ->  n := errf.Std.CheckInt(strconv.Atoi(s))
+//      cfg := errf.CheckAny(load()).(*Config)     ->  cfg := errf.Check(load())
+//  * errors returned directly after 'defer errf.IfError()...ThenAssignTo(&err)' handler:
+//      return 0, err  ->  return 0, errf.CheckErr(err).IfOkReturnNil
+//  * CheckAny with a type assertion, which never succeeds (no suggested fix):
+//      var n int64 = errf.CheckAny(strconv.Atoi(s)).(int64)
+package modernize
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/serhiy-t/errf/internal/analysisutil"
+	"github.com/serhiy-t/errf/internal/typedcheck"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Analyzer reports CheckAny type assertions and errors returned directly
+// from functions with IfError() handler.
+var Analyzer = &analysis.Analyzer{
+	Name:     "modernize",
+	Doc:      "report errflow usages, which can be replaced with recommended ones",
+	URL:      "https://pkg.go.dev/github.com/serhiy-t/errf/analysis/modernize",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.TypeAssertExpr)(nil),
+		(*ast.ReturnStmt)(nil),
+	}
+	inspect.WithStack(nodeFilter, func(node ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		switch node := node.(type) {
+		case *ast.TypeAssertExpr:
+			checkAnyAssert(pass, node, stack)
+		case *ast.ReturnStmt:
+			checkReturn(pass, node, stack)
+		}
+		return true
+	})
+	return nil, nil
+}
+
+// valueCall returns value type of (value, error) function call.
+func valueCall(pass *analysis.Pass, expr ast.Expr) (types.Type, bool) {
+	if _, ok := ast.Unparen(expr).(*ast.CallExpr); !ok {
+		return nil, false
+	}
+	results, ok := pass.TypesInfo.TypeOf(expr).(*types.Tuple)
+	if !ok || results.Len() != 2 || !analysisutil.IsErrorType(results.At(1).Type()) {
+		return nil, false
+	}
+	return results.At(0).Type(), true
+}
+
+// errfCall returns Check* function name and its receiver (nil for package-level functions),
+// if expr is a call of errflow Check* function with a single (value, error) call argument.
+func errfCall(pass *analysis.Pass, expr ast.Expr) (*ast.CallExpr, string, ast.Expr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, "", nil
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, "", nil
+	}
+	name := analysisutil.ErrfObject(pass.TypesInfo, selector.Sel)
+	if !strings.HasPrefix(name, "Check") {
+		return nil, "", nil
+	}
+	if _, ok := valueCall(pass, call.Args[0]); !ok {
+		return nil, "", nil
+	}
+	if _, isPackage := pass.TypesInfo.Uses[identOf(selector.X)].(*types.PkgName); isPackage {
+		return call, name, nil
+	}
+	return call, name, selector.X
+}
+
+func identOf(expr ast.Expr) *ast.Ident {
+	ident, _ := expr.(*ast.Ident)
+	return ident
+}
+
+// receiverChain parses Check* receiver: 'errf.With(opts)', 'errf.Std' or 'errf.Std.With(opts)'.
+// It returns table name (empty for *Errflow receiver) and With(...) call, if any.
+func receiverChain(pass *analysis.Pass, receiver ast.Expr) (table string, with *ast.CallExpr, ok bool) {
+	if call, isCall := receiver.(*ast.CallExpr); isCall {
+		selector, isSelector := call.Fun.(*ast.SelectorExpr)
+		if !isSelector || analysisutil.ErrfObject(pass.TypesInfo, selector.Sel) != "With" {
+			return "", nil, false
+		}
+		if _, isPackage := pass.TypesInfo.Uses[identOf(selector.X)].(*types.PkgName); isPackage {
+			return "", call, true
+		}
+		table, _, ok := receiverChain(pass, selector.X)
+		return table, call, ok && table != ""
+	}
+	if selector, isSelector := receiver.(*ast.SelectorExpr); isSelector {
+		if _, isVar := pass.TypesInfo.Uses[selector.Sel].(*types.Var); isVar {
+			if name := analysisutil.ErrfObject(pass.TypesInfo, selector.Sel); name != "" && name != "DefaultErrflow" {
+				return name, nil, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// typedCheck returns typed or generic Check* call for valueType, which keeps With(...) options.
+func typedCheck(pass *analysis.Pass, errfName string, with *ast.CallExpr, valueType types.Type, arg ast.Expr) (string, bool) {
+	argText := analysisutil.Render(pass.Fset, arg)
+	if table, method := typedcheck.Lookup(valueType); table != "" {
+		withText := ""
+		if with != nil {
+			var args []string
+			for _, arg := range with.Args {
+				args = append(args, analysisutil.Render(pass.Fset, arg))
+			}
+			if with.Ellipsis.IsValid() {
+				args[len(args)-1] += "..."
+			}
+			withText = ".With(" + strings.Join(args, ", ") + ")"
+		}
+		return fmt.Sprintf("%s.%s%s.%s(%s)", errfName, table, withText, method, argText), true
+	}
+	if with == nil {
+		return fmt.Sprintf("%s.Check(%s)", errfName, argText), true
+	}
+	return "", false
+}
+
+func typeString(pass *analysis.Pass, t types.Type) string {
+	return types.TypeString(t, types.RelativeTo(pass.Pkg))
+}
+
+func checkAnyAssert(pass *analysis.Pass, expr *ast.TypeAssertExpr, stack []ast.Node) {
+	if expr.Type == nil {
+		return
+	}
+	call, name, receiver := errfCall(pass, expr.X)
+	if call == nil || name != "CheckAny" {
+		return
+	}
+	valueType, _ := valueCall(pass, call.Args[0])
+	assertType := pass.TypesInfo.TypeOf(expr.Type)
+	if !types.Identical(valueType, assertType) {
+		if neverAssertable(valueType, assertType) {
+			pass.Reportf(expr.Pos(), "CheckAny(...).(%s) always panics: function returns %s",
+				typeString(pass, assertType), typeString(pass, valueType))
+		}
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: fmt.Sprintf("CheckAny(...).(%s) can be replaced with typed check", typeString(pass, valueType)),
+	}
+	var with *ast.CallExpr
+	ok := true
+	if receiver != nil {
+		var table string
+		table, with, ok = receiverChain(pass, receiver)
+		ok = ok && table == ""
+	}
+	if ok {
+		errfName, _ := analysisutil.ErrfImportName(analysisutil.FileOf(stack))
+		if text, ok := typedCheck(pass, errfName, with, valueType, call.Args[0]); ok {
+			diagnostic.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   "Use typed check",
+				TextEdits: []analysis.TextEdit{{Pos: expr.Pos(), End: expr.End(), NewText: []byte(text)}},
+			}}
+		}
+	}
+	pass.Report(diagnostic)
+}
+
+// neverAssertable reports whether value of valueType, converted to interface{},
+// can never be asserted to assertType.
+func neverAssertable(valueType, assertType types.Type) bool {
+	if iface, ok := valueType.Underlying().(*types.Interface); ok {
+		return !types.IsInterface(assertType) && !types.AssertableTo(iface, assertType)
+	}
+	if iface, ok := assertType.Underlying().(*types.Interface); ok {
+		return !types.Implements(valueType, iface)
+	}
+	return true
+}
+
+func checkReturn(pass *analysis.Pass, stmt *ast.ReturnStmt, stack []ast.Node) {
+	fn := analysisutil.EnclosingFunc(stack)
+	if fn == nil || fn.Type.Results == nil || len(stmt.Results) != fn.Type.Results.NumFields() || len(stmt.Results) == 0 {
+		return
+	}
+	resultFields := fn.Type.Results.List
+	if !analysisutil.IsErrorType(pass.TypesInfo.TypeOf(resultFields[len(resultFields)-1].Type)) {
+		return
+	}
+	errExpr := stmt.Results[len(stmt.Results)-1]
+	if pass.TypesInfo.Types[errExpr].IsNil() {
+		return
+	}
+	if selector, ok := ast.Unparen(errExpr).(*ast.SelectorExpr); ok &&
+		analysisutil.ErrfObject(pass.TypesInfo, selector.Sel) == "IfOkReturnNil" {
+		return
+	}
+	// Only errors assigned by the IfError() handler to the returned error result
+	// are replaced, so direct returns before the handler or with
+	// Then(...)/ThenIgnore() are left as is.
+	scope := analysisutil.IfErrorScope(pass.TypesInfo, fn, stmt.Pos())
+	if scope == nil || !analysisutil.AssignsTo(pass.TypesInfo, scope, errorResultVar(pass, fn)) {
+		return
+	}
+
+	errfName, _ := analysisutil.ErrfImportName(analysisutil.FileOf(stack))
+	pass.Report(analysis.Diagnostic{
+		Pos:     errExpr.Pos(),
+		End:     errExpr.End(),
+		Message: "error is returned directly from function with IfError() handler",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "Use errf.CheckErr(...).IfOkReturnNil",
+			TextEdits: []analysis.TextEdit{{
+				Pos:     errExpr.Pos(),
+				End:     errExpr.End(),
+				NewText: []byte(fmt.Sprintf("%s.CheckErr(%s).IfOkReturnNil", errfName, analysisutil.Render(pass.Fset, errExpr))),
+			}},
+		}},
+	})
+}
+
+// errorResultVar returns named error result of fn or nil, if it is unnamed.
+func errorResultVar(pass *analysis.Pass, fn *analysisutil.Function) types.Object {
+	resultFields := fn.Type.Results.List
+	names := resultFields[len(resultFields)-1].Names
+	if len(names) == 0 {
+		return nil
+	}
+	return pass.TypesInfo.Defs[names[len(names)-1]]
+}
@@ -0,0 +1,115 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/serhiy-t/errf"
+)
+
+type config struct{}
+
+func load() (*config, error) {
+	return &config{}, nil
+}
+
+func open() (interface{}, error) {
+	return nil, nil
+}
+
+func checkAny(s string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	n := errf.CheckAny(strconv.Atoi(s)).(int)                                      // want `CheckAny\(...\).\(int\) can be replaced with typed check`
+	cfg := errf.CheckAny(load()).(*config)                                         // want `CheckAny\(...\).\(\*config\) can be replaced with typed check`
+	m := errf.With(errf.WrapperFmtErrorw("parse")).CheckAny(strconv.Atoi(s)).(int) // want `CheckAny\(...\).\(int\) can be replaced with typed check`
+	other := errf.With(errf.WrapperFmtErrorw("load")).CheckAny(load()).(*config)   // want `CheckAny\(...\).\(\*config\) can be replaced with typed check`
+	value := errf.CheckAny(open()).(*config)
+	_, _, _, _, _ = n, cfg, m, other, value
+	return nil
+}
+
+func directReturns(s string) (_ int, err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err // want `error is returned directly from function with IfError\(\) handler`
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("negative: %d", n) // want `error is returned directly from function with IfError\(\) handler`
+	}
+	fn := func() error {
+		return errors.New("not in scope")
+	}
+	if n == 0 {
+		return 0, errf.CheckErr(fn()).IfOkReturnNil
+	}
+	return n, nil
+}
+
+func returnsOutsideScope(s string) (_ int, err error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	defer errf.IfError().ThenAssignTo(&err)
+
+	if n < 0 {
+		return 0, fmt.Errorf("negative: %d", n) // want `error is returned directly from function with IfError\(\) handler`
+	}
+	return n, nil
+}
+
+func returnsWithThen(s string) (int, error) {
+	defer errf.IfError().Then(func(err error) {})
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func returnsWithThenIgnore(s string) (_ int, err error) {
+	defer errf.IfError().ThenIgnore()
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func returnsWithOtherTarget(s string) (_ int, err error) {
+	var other error
+	defer errf.IfError().ThenAssignTo(&other)
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, other
+}
+
+func noScope(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func mismatches(s string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	var n int64 = errf.CheckAny(strconv.Atoi(s)).(int64) // want `CheckAny\(...\).\(int64\) always panics: function returns int`
+	var w io.Writer = errf.CheckAny(load()).(io.Writer)  // want `CheckAny\(...\).\(io.Writer\) always panics: function returns \*config`
+	f := errf.CheckAny(os.Open(s)).(io.Reader)
+	v := errf.CheckAny(open()).(int)
+	_, _, _, _ = n, w, f, v
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package errf is a minimal errflow API stub for analyzer tests.
+package errf
+
+import (
+	"io"
+	"os"
+)
+
+type IfErrorHandler struct{}
+
+func IfError() *IfErrorHandler { return &IfErrorHandler{} }
+
+func (c *IfErrorHandler) ThenAssignTo(outErr *error) {}
+
+func (c *IfErrorHandler) Then(fn func(err error)) {}
+
+func (c *IfErrorHandler) ThenIgnore() {}
+
+type CheckResult struct {
+	IfOkReturnNil error
+}
+
+type Errflow struct{}
+
+type ErrflowOption func(ef *Errflow) *Errflow
+
+var DefaultErrflow = &Errflow{}
+
+func With(options ...ErrflowOption) *Errflow { return &Errflow{} }
+
+func WrapperFmtErrorw(s string) ErrflowOption { return nil }
+
+func (ef *Errflow) CheckAny(value interface{}, err error) interface{} { return value }
+
+func CheckAny(value interface{}, err error) interface{} { return value }
+
+func CheckErr(err error) CheckResult { return CheckResult{} }
+
+func Check[T any](value T, err error) T { return value }
+
+var Std = StdErrflow{}
+
+type StdErrflow struct{}
+
+func (ef StdErrflow) With(options ...ErrflowOption) StdErrflow { return ef }
+
+func (ef StdErrflow) CheckInt(value int, err error) int { return value }
+
+var Io = IoErrflow{}
+
+type IoErrflow struct{}
+
+func (ef IoErrflow) With(options ...ErrflowOption) IoErrflow { return ef }
+
+func (ef IoErrflow) CheckWriter(value io.Writer, err error) io.Writer { return value }
+
+func (ef IoErrflow) CheckReader(value io.Reader, err error) io.Reader { return value }
+
+var Os = OsErrflow{}
+
+type OsErrflow struct{}
+
+func (ef OsErrflow) CheckFile(value *os.File, err error) *os.File { return value }
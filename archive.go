@@ -0,0 +1,122 @@
+package errf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"io"
+)
+
+// Archive contains collection of Check* functions for archive/* types.
+var Archive = ArchiveErrflow{}
+
+// ArchiveErrflow implements Check* functions for archive/* packages types.
+//
+// Clients should not instantiate ArchiveErrflow, use 'errf.Archive' instead.
+type ArchiveErrflow struct {
+	errflow *Errflow
+}
+
+// With implements Errflow.With(...) for archive/* types.
+func (ef ArchiveErrflow) With(options ...ErrflowOption) ArchiveErrflow {
+	return ArchiveErrflow{errflow: ef.errflow.With(options...)}
+}
+
+// CheckZipReader calls errf.Check and returns a typed value from a function call.
+func (ef ArchiveErrflow) CheckZipReader(value *zip.Reader, err error) *zip.Reader {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckZipReadCloser calls errf.Check and returns a typed value from a function call.
+func (ef ArchiveErrflow) CheckZipReadCloser(value *zip.ReadCloser, err error) *zip.ReadCloser {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckZipReaderErr calls errf.Check and returns a typed value and error from a function call.
+func (ef ArchiveErrflow) CheckZipReaderErr(value *zip.Reader, err error) (*zip.Reader, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckZipReadCloserErr calls errf.Check and returns a typed value and error from a function call.
+func (ef ArchiveErrflow) CheckZipReadCloserErr(value *zip.ReadCloser, err error) (*zip.ReadCloser, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckTarNext calls errf.Check on result of tar.Reader.Next() call and returns a header.
+// io.EOF is treated as end of archive: nil header is returned, without an error.
+//
+// Example:
+//  tarReader := tar.NewReader(reader)
+//  for header := errf.Archive.CheckTarNext(tarReader.Next()); header != nil;
+//  	header = errf.Archive.CheckTarNext(tarReader.Next()) {
+//  	// ...
+//  }
+func (ef ArchiveErrflow) CheckTarNext(header *tar.Header, err error) *tar.Header {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	ef.errflow.ImplementCheck(recover(), err)
+	return header
+}
+
+// ZipWriter creates zip.Writer for writer, calls fn and then closes zip.Writer.
+//
+// fn is executed in an implicit IfError() scope, so it can use Check* functions
+// without setting up IfError() handler.
+// zip.Writer is always closed, even if fn fails.
+// Errors from fn and Close() are combined according to return strategy.
+//
+// Example:
+//  func zipFiles(dst string, files map[string][]byte) (err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	writer := errf.Io.CheckWriteCloser(os.Create(dst))
+//  	defer errf.CheckDeferErr(writer.Close)
+//
+//  	errf.Archive.ZipWriter(writer, func(zipWriter *zip.Writer) {
+//  		for name, data := range files {
+//  			errf.Archive.AddZipFile(zipWriter, name, bytes.NewReader(data))
+//  		}
+//  	})
+//  	return nil
+//  }
+func (ef ArchiveErrflow) ZipWriter(writer io.Writer, fn func(zipWriter *zip.Writer)) CheckResult {
+	zipWriter := zip.NewWriter(writer)
+	fnErr := Func1(fn)(zipWriter)
+	return ef.errflow.implementCheckAll(recover(), []error{fnErr, zipWriter.Close()})
+}
+
+// TarWriter creates tar.Writer for writer, calls fn and then closes tar.Writer.
+//
+// See ZipWriter for details.
+func (ef ArchiveErrflow) TarWriter(writer io.Writer, fn func(tarWriter *tar.Writer)) CheckResult {
+	tarWriter := tar.NewWriter(writer)
+	fnErr := Func1(fn)(tarWriter)
+	return ef.errflow.implementCheckAll(recover(), []error{fnErr, tarWriter.Close()})
+}
+
+// AddZipFile adds a file with a name and content from reader to zipWriter.
+func (ef ArchiveErrflow) AddZipFile(zipWriter *zip.Writer, name string, reader io.Reader) CheckResult {
+	fileWriter, err := zipWriter.Create(name)
+	if err == nil {
+		_, err = io.Copy(fileWriter, reader)
+	}
+	return ef.errflow.ImplementCheck(recover(), err)
+}
+
+// AddTarFile adds a file with a header and content from reader to tarWriter.
+// header.Size should match content size.
+func (ef ArchiveErrflow) AddTarFile(tarWriter *tar.Writer, header *tar.Header, reader io.Reader) CheckResult {
+	err := tarWriter.WriteHeader(header)
+	if err == nil {
+		_, err = io.Copy(tarWriter, reader)
+	}
+	if err == nil {
+		err = tarWriter.Flush()
+	}
+	return ef.errflow.ImplementCheck(recover(), err)
+}
@@ -0,0 +1,105 @@
+package errf
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Archive_With(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Archive.With(WrapperFmtErrorw("wrapped")).CheckZipReader(nil, fmt.Errorf("error"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: error")
+}
+
+func Test_Archive_Zip(t *testing.T) {
+	var buf bytes.Buffer
+
+	writeFn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Archive.ZipWriter(&buf, func(zipWriter *zip.Writer) {
+			Archive.AddZipFile(zipWriter, "a.txt", strings.NewReader("aaa"))
+			Archive.AddZipFile(zipWriter, "b.txt", strings.NewReader("bbb"))
+		})
+		return nil
+	}
+
+	readFn := func() (files map[string]string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		files = make(map[string]string)
+		zipReader := Archive.CheckZipReader(zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len())))
+		for _, file := range zipReader.File {
+			reader := Io.CheckReadCloser(file.Open())
+			files[file.Name] = string(CheckAny(io.ReadAll(reader)).([]byte))
+			CheckErr(reader.Close())
+		}
+		return files, nil
+	}
+
+	assert.NoError(t, writeFn())
+	files, err := readFn()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.txt": "aaa", "b.txt": "bbb"}, files)
+
+	buf.Truncate(10)
+	_, err = readFn()
+	assert.EqualError(t, err, "zip: not a valid zip file")
+}
+
+func Test_Archive_ZipWriter_Errors(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		Archive.ZipWriter(&limitedWriter{limit: 0}, func(zipWriter *zip.Writer) {
+			CheckErr(fmt.Errorf("fn error"))
+		})
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "combined error {fn error; no space left}")
+}
+
+func Test_Archive_Tar(t *testing.T) {
+	var buf bytes.Buffer
+
+	writeFn := func(size int64) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		buf.Reset()
+		Archive.TarWriter(&buf, func(tarWriter *tar.Writer) {
+			Archive.AddTarFile(tarWriter, &tar.Header{Name: "a.txt", Mode: 0600, Size: size}, strings.NewReader("aaa"))
+			Archive.AddTarFile(tarWriter, &tar.Header{Name: "b.txt", Mode: 0600, Size: 3}, strings.NewReader("bbb"))
+		})
+		return nil
+	}
+
+	readFn := func() (names []string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		tarReader := tar.NewReader(&buf)
+		for header := Archive.CheckTarNext(tarReader.Next()); header != nil; header = Archive.CheckTarNext(tarReader.Next()) {
+			names = append(names, header.Name)
+		}
+		return names, nil
+	}
+
+	assert.NoError(t, writeFn(3))
+	names, err := readFn()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, names)
+
+	assert.EqualError(t, writeFn(2), "archive/tar: write too long")
+	assert.EqualError(t, writeFn(4), "archive/tar: missed writing 1 bytes")
+
+	assert.NoError(t, writeFn(3))
+	buf.Truncate(1100)
+	_, err = readFn()
+	assert.EqualError(t, err, "unexpected EOF")
+}
@@ -0,0 +1,119 @@
+package errf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errBenchmark = fmt.Errorf("benchmark error")
+
+//go:noinline
+func benchmarkOp(fail bool) (int, error) {
+	if fail {
+		return 0, errBenchmark
+	}
+	return 1, nil
+}
+
+func benchmarkPlain(fail bool) (result int, err error) {
+	for i := 0; i < 10; i++ {
+		value, err := benchmarkOp(fail && i == 9)
+		if err != nil {
+			return 0, err
+		}
+		result += value
+	}
+	return result, nil
+}
+
+func benchmarkErrflow(fail bool) (result int, err error) {
+	defer IfError().ThenAssignTo(&err)
+
+	for i := 0; i < 10; i++ {
+		result += Std.CheckInt(benchmarkOp(fail && i == 9))
+	}
+	return result, nil
+}
+
+func Benchmark_Plain_Success(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkPlain(false)
+	}
+}
+
+func Benchmark_Plain_Failure(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkPlain(true)
+	}
+}
+
+func Benchmark_Errflow_Success(b *testing.B) {
+	defer SetNoopValidator().ThenRestore()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkErrflow(false)
+	}
+}
+
+func Benchmark_Errflow_Failure(b *testing.B) {
+	defer SetNoopValidator().ThenRestore()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkErrflow(true)
+	}
+}
+
+func Benchmark_Errflow_Success_StackTraceValidator(b *testing.B) {
+	defer SetStackTraceValidator().ThenRestore()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchmarkErrflow(false)
+	}
+}
+
+func Benchmark_CheckErr_Success(b *testing.B) {
+	defer SetNoopValidator().ThenRestore()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CheckErr(nil)
+	}
+}
+
+func Benchmark_Errflow_Failure_LogAlways(b *testing.B) {
+	defer SetNoopValidator().ThenRestore()
+	defer SetLogFn(func(logMessage *LogMessage) {}).ThenRestore()
+	fn := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		CheckErr(errBenchmark)
+		return nil
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fn()
+	}
+}
+
+func Test_Errflow_Success_ZeroAllocs(t *testing.T) {
+	defer SetNoopValidator().ThenRestore()
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = benchmarkErrflow(false)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func Test_CommaOk_Success_ZeroAllocs(t *testing.T) {
+	defer SetNoopValidator().ThenRestore()
+	m := map[string]int{"a": 1}
+	fn := func() (result int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return CheckKey(m, "a") + CheckOk(2, true, "not found"), nil
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = fn()
+	})
+	assert.Equal(t, 0.0, allocs)
+}
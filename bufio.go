@@ -2,6 +2,7 @@ package errf
 
 import (
 	"bufio"
+	"iter"
 )
 
 // Bufio contains collection of Check* functions for bufio.* types.
@@ -40,17 +41,36 @@ func (ef BufioErrflow) CheckReadWriter(value *bufio.ReadWriter, err error) *bufi
 // CheckWriterErr calls errflow.Check and returns a typed value and error from a function call.
 func (ef BufioErrflow) CheckWriterErr(value *bufio.Writer, err error) (*bufio.Writer, error) {
 	ef.errflow.ImplementCheck(recover(), err)
-	return value, nil
+	return value, err
 }
 
 // CheckReaderErr calls errflow.Check and returns a typed value and error from a function call.
 func (ef BufioErrflow) CheckReaderErr(value *bufio.Reader, err error) (*bufio.Reader, error) {
 	ef.errflow.ImplementCheck(recover(), err)
-	return value, nil
+	return value, err
 }
 
 // CheckReadWriterErr calls errflow.Check and returns a typed value and error from a function call.
 func (ef BufioErrflow) CheckReadWriterErr(value *bufio.ReadWriter, err error) (*bufio.ReadWriter, error) {
 	ef.errflow.ImplementCheck(recover(), err)
-	return value, nil
+	return value, err
+}
+
+// CheckDeferFlush calls writer.Flush() and checks for return error.
+// Useful in defer statements:
+//  bufWriter := bufio.NewWriter(writer)
+//  defer errf.Bufio.CheckDeferFlush(bufWriter)
+func (ef BufioErrflow) CheckDeferFlush(writer *bufio.Writer) CheckResult {
+	return ef.errflow.ImplementCheck(recover(), writer.Flush())
+}
+
+// Lines creates a sequence of scanner tokens (scanner.Text()).
+// After the last token, scanner.Err() is checked.
+//
+// Example:
+//  for line := range errf.Bufio.Lines(bufio.NewScanner(reader)) {
+//  	// ...
+//  }
+func (ef BufioErrflow) Lines(scanner *bufio.Scanner) iter.Seq[string] {
+	return each(ef.errflow, ScannerSeq(scanner))
 }
@@ -3,6 +3,8 @@ package errf
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,3 +105,47 @@ func Test_Bufio_CheckBufioReadWriterErr(t *testing.T) {
 
 	assert.EqualError(t, fn(), "error")
 }
+
+func Test_Bufio_CheckDeferFlush(t *testing.T) {
+	fn := func(writer io.Writer) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		bufWriter := bufio.NewWriter(writer)
+		defer Bufio.CheckDeferFlush(bufWriter)
+		_, _ = bufWriter.WriteString("hello")
+		return nil
+	}
+
+	writer := &limitedWriter{limit: 10}
+	assert.NoError(t, fn(writer))
+	assert.Equal(t, "hello", writer.buf.String())
+
+	assert.EqualError(t, fn(&limitedWriter{limit: 2}), "no space left")
+}
+
+func Test_Bufio_Lines(t *testing.T) {
+	fn := func(reader io.Reader) (lines []string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		for line := range Bufio.Lines(bufio.NewScanner(reader)) {
+			lines = append(lines, line)
+		}
+		return lines, nil
+	}
+
+	lines, err := fn(strings.NewReader("a\nb"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, lines)
+
+	_, err = fn(io.MultiReader(strings.NewReader("a\n"), errReader{}))
+	assert.EqualError(t, err, "read error")
+}
+
+func Test_Bufio_Lines_With(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		for range Bufio.With(WrapperFmtErrorw("wrapped")).Lines(bufio.NewScanner(errReader{})) {
+		}
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: read error")
+}
@@ -0,0 +1,143 @@
+package errf
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// callbackScope creates IfErrorHandler for a callback body fn,
+// which is executed by one of callback adapters (Func0, Func1, ...).
+//
+// Unlike IfError(), handler scope is fn itself, rather than a function
+// which creates the handler.
+func callbackScope(fn interface{}) *IfErrorHandler {
+	if validatorEnabled {
+		globalErrflowValidator.enterCallback(fn)
+	}
+	c := &IfErrorHandler{callback: true}
+	if globalTracer != nil {
+		c.trace = enterTrace(getFnName(fn))
+	}
+	return c
+}
+
+// Func0 converts fn into a callback, which returns an error.
+//
+// fn is executed in an implicit IfError() scope, so it can use Check* functions
+// without setting up IfError() handler. Errors are returned from the callback.
+//
+// Example:
+//  group.Go(errf.Func0(func() {
+//  	writer := errf.Io.CheckWriteCloser(os.Create(filename))
+//  	defer errf.CheckDeferErr(writer.Close)
+//  	// ...
+//  }))
+func Func0(fn func()) func() error {
+	return func() (err error) {
+		defer callbackScope(fn).ThenAssignTo(&err)
+		fn()
+		return nil
+	}
+}
+
+// Func1 is same as Func0 for callbacks with 1 argument.
+func Func1[A any](fn func(A)) func(A) error {
+	return func(a A) (err error) {
+		defer callbackScope(fn).ThenAssignTo(&err)
+		fn(a)
+		return nil
+	}
+}
+
+// Func2 is same as Func0 for callbacks with 2 arguments.
+func Func2[A, B any](fn func(A, B)) func(A, B) error {
+	return func(a A, b B) (err error) {
+		defer callbackScope(fn).ThenAssignTo(&err)
+		fn(a, b)
+		return nil
+	}
+}
+
+// Func1AssignTo converts fn into a callback for APIs, which don't support errors in callbacks
+// (e.g. comparators or predicates).
+//
+// fn is executed in an implicit IfError() scope, so it can use Check* functions
+// without setting up IfError() handler.
+// First error is assigned to outErr and zero value is returned from the callback.
+// Once outErr is non-nil, fn is not called anymore.
+func Func1AssignTo[A, R any](outErr *error, fn func(A) R) func(A) R {
+	return func(a A) (result R) {
+		if *outErr != nil {
+			return result
+		}
+		defer callbackScope(fn).ThenAssignTo(outErr)
+		return fn(a)
+	}
+}
+
+// Func2AssignTo is same as Func1AssignTo for callbacks with 2 arguments.
+//
+// Example:
+//  func sumValues(m *sync.Map) (sum int, err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	var rangeErr error
+//  	m.Range(errf.Func2AssignTo(&rangeErr, func(key, value interface{}) bool {
+//  		sum += errf.CheckType[int](value)
+//  		return true
+//  	}))
+//  	return sum, errf.CheckErr(rangeErr).IfOkReturnNil
+//  }
+//
+// Since sync.Map.Range stops when callback returns false,
+// it stops on the first error.
+func Func2AssignTo[A, B, R any](outErr *error, fn func(A, B) R) func(A, B) R {
+	return func(a A, b B) (result R) {
+		if *outErr != nil {
+			return result
+		}
+		defer callbackScope(fn).ThenAssignTo(outErr)
+		return fn(a, b)
+	}
+}
+
+// WalkDirFunc converts fn into fs.WalkDirFunc (which is also usable with filepath.WalkDir).
+//
+// fn is executed in an implicit IfError() scope, so it can use Check* functions
+// without setting up IfError() handler. Errors are returned from the callback.
+// Errors reported by fs.WalkDir are returned without calling fn.
+//
+// Tip: errf.CheckErr(fs.SkipDir) can be used to skip a directory.
+//
+// Example:
+//  func totalSize(root string) (size int64, err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	errf.CheckErr(filepath.WalkDir(root, errf.WalkDirFunc(func(path string, d fs.DirEntry) {
+//  		info := errf.CheckAny(d.Info()).(fs.FileInfo)
+//  		size += info.Size()
+//  	})))
+//  	return size, nil
+//  }
+func WalkDirFunc(fn func(path string, d fs.DirEntry)) fs.WalkDirFunc {
+	return func(path string, d fs.DirEntry, walkErr error) (err error) {
+		if walkErr != nil {
+			return walkErr
+		}
+		defer callbackScope(fn).ThenAssignTo(&err)
+		fn(path, d)
+		return nil
+	}
+}
+
+// WalkFunc is same as WalkDirFunc for filepath.Walk.
+func WalkFunc(fn func(path string, info fs.FileInfo)) filepath.WalkFunc {
+	return func(path string, info fs.FileInfo, walkErr error) (err error) {
+		if walkErr != nil {
+			return walkErr
+		}
+		defer callbackScope(fn).ThenAssignTo(&err)
+		fn(path, info)
+		return nil
+	}
+}
@@ -0,0 +1,190 @@
+package errf
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Func0(t *testing.T) {
+	callback := Func0(func() {
+		CheckErr(fmt.Errorf("error"))
+	})
+
+	assert.EqualError(t, callback(), "error")
+	assert.NoError(t, Func0(func() {})())
+}
+
+func Test_Func1(t *testing.T) {
+	callback := Func1(func(value int) {
+		CheckCondition(value < 0, "negative value: %d", value)
+	})
+
+	assert.NoError(t, callback(1))
+	assert.EqualError(t, callback(-1), "negative value: -1")
+}
+
+type testCallbackReceiver struct {
+	values []int
+}
+
+func (r *testCallbackReceiver) add(value int) {
+	CheckCondition(value < 0, "negative value: %d", value)
+	r.values = append(r.values, value)
+}
+
+func Test_Func1_MethodValue(t *testing.T) {
+	receiver := &testCallbackReceiver{}
+	callback := Func1(receiver.add)
+
+	assert.NotPanics(t, func() {
+		assert.NoError(t, callback(1))
+		assert.EqualError(t, callback(-1), "negative value: -1")
+	})
+	assert.Equal(t, []int{1}, receiver.values)
+}
+
+func Test_Func2(t *testing.T) {
+	callback := Func2(func(a, b int) {
+		CheckCondition(a > b, "%d > %d", a, b)
+	})
+
+	assert.NoError(t, callback(1, 2))
+	assert.EqualError(t, callback(2, 1), "2 > 1")
+}
+
+func Test_Func1_UnrelatedPanic(t *testing.T) {
+	callback := Func1(func(value int) {
+		panic(value)
+	})
+
+	assert.PanicsWithValue(t, 1, func() {
+		_ = callback(1)
+	})
+}
+
+func Test_Func2AssignTo_SyncMapRange(t *testing.T) {
+	fn := func(m *sync.Map) (sum int, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		var rangeErr error
+		m.Range(Func2AssignTo(&rangeErr, func(key, value interface{}) bool {
+			sum += CheckType[int](value)
+			return true
+		}))
+		return sum, CheckErr(rangeErr).IfOkReturnNil
+	}
+
+	var m sync.Map
+	m.Store("a", 1)
+	m.Store("b", 2)
+	sum, err := fn(&m)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, sum)
+
+	m.Store("c", "three")
+	_, err = fn(&m)
+	assert.EqualError(t, err, "type assertion failed: string is not int")
+}
+
+func Test_Func2AssignTo_Sort(t *testing.T) {
+	fn := func(values []string) (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		var sortErr error
+		sort.Slice(values, Func2AssignTo(&sortErr, func(i, j int) bool {
+			CheckCondition(values[i] == "" || values[j] == "", "empty value")
+			return values[i] < values[j]
+		}))
+		return CheckErr(sortErr).IfOkReturnNil
+	}
+
+	values := []string{"c", "a", "b"}
+	assert.NoError(t, fn(values))
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+
+	assert.EqualError(t, fn([]string{"c", "", "b"}), "empty value")
+}
+
+func Test_Func1AssignTo(t *testing.T) {
+	var outErr error
+	calls := 0
+	callback := Func1AssignTo(&outErr, func(value int) bool {
+		calls++
+		CheckCondition(value < 0, "negative value: %d", value)
+		return true
+	})
+
+	assert.True(t, callback(1))
+	assert.False(t, callback(-1))
+	assert.False(t, callback(2))
+	assert.EqualError(t, outErr, "negative value: -1")
+	assert.Equal(t, 2, calls)
+}
+
+func Test_WalkDirFunc(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     &fstest.MapFile{Data: []byte("a")},
+		"dir/b.txt": &fstest.MapFile{Data: []byte("bb")},
+		"dir/c.txt": &fstest.MapFile{Data: []byte("ccc")},
+	}
+
+	fn := func(maxSize int64) (size int64, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		CheckErr(fs.WalkDir(fsys, ".", WalkDirFunc(func(path string, d fs.DirEntry) {
+			info := CheckAny(d.Info()).(fs.FileInfo)
+			CheckCondition(info.Size() > maxSize, "file %s is too large", path)
+			size += info.Size()
+		})))
+		return size, nil
+	}
+
+	size, err := fn(10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), size)
+
+	_, err = fn(2)
+	assert.EqualError(t, err, "file dir/c.txt is too large")
+}
+
+func Test_WalkDirFunc_SkipDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.txt":     &fstest.MapFile{},
+		"dir/b.txt": &fstest.MapFile{},
+	}
+
+	var paths []string
+	err := fs.WalkDir(fsys, ".", WalkDirFunc(func(path string, d fs.DirEntry) {
+		if path == "dir" {
+			CheckErr(fs.SkipDir)
+		}
+		paths = append(paths, path)
+	}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".", "a.txt"}, paths)
+}
+
+func Test_WalkDirFunc_WalkError(t *testing.T) {
+	called := false
+	err := WalkDirFunc(func(path string, d fs.DirEntry) {
+		called = true
+	})(".", nil, fmt.Errorf("walk error"))
+
+	assert.EqualError(t, err, "walk error")
+	assert.False(t, called)
+}
+
+func Test_WalkFunc(t *testing.T) {
+	err := WalkFunc(func(path string, info fs.FileInfo) {
+		CheckErr(fmt.Errorf("error in %s", path))
+	})("path", nil, nil)
+
+	assert.EqualError(t, err, "error in path")
+}
@@ -0,0 +1,972 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/serhiy-t/errf/internal/srcedit"
+)
+
+const errfPath = "github.com/serhiy-t/errf"
+
+// optionSet is a static representation of resolved errflow options.
+type optionSet struct {
+	wrappers       []func(err string) string
+	returnStrategy string
+	logStrategy    string
+}
+
+// option mirrors ErrflowOption: strategies are set only once (first wins),
+// wrappers are applied in order.
+type option func(s *optionSet)
+
+func resolveOptions(options ...[]option) optionSet {
+	var s optionSet
+	for _, list := range options {
+		for _, opt := range list {
+			opt(&s)
+		}
+	}
+	if s.returnStrategy == "" {
+		s.returnStrategy = "First"
+	}
+	if s.logStrategy == "" {
+		s.logStrategy = "Never"
+	}
+	return s
+}
+
+func (s optionSet) wrap(err string) string {
+	for _, wrapper := range s.wrappers {
+		err = wrapper(err)
+	}
+	return err
+}
+
+func returnStrategyOption(rs string) option {
+	return func(s *optionSet) {
+		if s.returnStrategy == "" {
+			s.returnStrategy = rs
+		}
+	}
+}
+
+func logStrategyOption(ls string) option {
+	return func(s *optionSet) {
+		if s.logStrategy == "" {
+			s.logStrategy = ls
+		}
+	}
+}
+
+func wrapperOption(wrapper func(err string) string) option {
+	return func(s *optionSet) {
+		s.wrappers = append(s.wrappers, wrapper)
+	}
+}
+
+var namedOptions = map[string]option{
+	"ReturnStrategyFirst":     returnStrategyOption("First"),
+	"ReturnStrategyLast":      returnStrategyOption("Last"),
+	"ReturnStrategyWrapped":   returnStrategyOption("Wrapped"),
+	"ReturnStrategyCombined":  returnStrategyOption("Combined"),
+	"LogStrategyNever":        logStrategyOption("Never"),
+	"LogStrategyIfSuppressed": logStrategyOption("IfSuppressed"),
+	"LogStrategyAlways":       logStrategyOption("Always"),
+	// Ejected code logs each error at most once, so LogForce has no effect.
+	"LogForce": func(s *optionSet) {},
+}
+
+var ifErrorMethods = map[string]string{
+	"ReturnFirst":     "ReturnStrategyFirst",
+	"ReturnLast":      "ReturnStrategyLast",
+	"ReturnWrapped":   "ReturnStrategyWrapped",
+	"ReturnCombined":  "ReturnStrategyCombined",
+	"LogNever":        "LogStrategyNever",
+	"LogIfSuppressed": "LogStrategyIfSuppressed",
+	"LogAlways":       "LogStrategyAlways",
+	"LogForce":        "LogForce",
+}
+
+type ejector struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+	pkg  *types.Package
+	info *types.Info
+
+	edits   []srcedit.Edit
+	notes   []string
+	imports map[string]bool
+}
+
+// ejectFunc holds state of a single function conversion.
+type ejectFunc struct {
+	*ejector
+	fd *ast.FuncDecl
+
+	errName     string
+	results     []string
+	scope       []option
+	edits       []srcedit.Edit
+	covered     [][2]token.Pos
+	imports     map[string]bool
+	// changes holds behavior changes, which are reported as notes, if function is converted.
+	changes []string
+	// optionVars holds definitions of local variables with options
+	// (e.g. 'errWrapper := errf.WrapperFmtErrorw(...)').
+	optionVars map[types.Object]*ast.AssignStmt
+}
+
+// ejectFile rewrites functions, which use errflow, to explicit Go error handling.
+// If funcName is not empty, only functions with this name are converted.
+func ejectFile(fset *token.FileSet, file *ast.File, src []byte, pkg *types.Package, info *types.Info, funcName string) ([]byte, []string, error) {
+	e := &ejector{fset: fset, file: file, src: src, pkg: pkg, info: info, imports: map[string]bool{}}
+
+	converted := false
+	for _, decl := range file.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil || (funcName != "" && fd.Name.Name != funcName) {
+			continue
+		}
+		if !e.usesErrf(fd.Body) {
+			continue
+		}
+		f := &ejectFunc{ejector: e, fd: fd, imports: map[string]bool{}, optionVars: map[types.Object]*ast.AssignStmt{}}
+		if reason := f.eject(); reason != "" {
+			e.note(fd, "skipping %s: %s", fd.Name.Name, reason)
+			continue
+		}
+		for _, change := range f.changes {
+			e.note(fd, "%s: %s", fd.Name.Name, change)
+		}
+		e.edits = append(e.edits, f.edits...)
+		for path := range f.imports {
+			e.imports[path] = true
+		}
+		converted = true
+	}
+	if !converted {
+		return src, e.notes, nil
+	}
+
+	result, err := srcedit.Apply(src, e.edits)
+	if err != nil {
+		return nil, e.notes, err
+	}
+	for _, path := range []string{"errors", "fmt", "log"} {
+		if e.imports[path] {
+			if result, err = srcedit.AddImport(result, path); err != nil {
+				return nil, e.notes, err
+			}
+		}
+	}
+	result, err = srcedit.RemoveUnusedImport(result, errfPath)
+	return result, e.notes, err
+}
+
+func (e *ejector) note(node ast.Node, format string, a ...interface{}) {
+	e.notes = append(e.notes, fmt.Sprintf("%s: %s", e.fset.Position(node.Pos()), fmt.Sprintf(format, a...)))
+}
+
+func (e *ejector) offset(pos token.Pos) int {
+	return e.fset.Position(pos).Offset
+}
+
+func (e *ejector) source(node ast.Node) string {
+	return string(e.src[e.offset(node.Pos()):e.offset(node.End())])
+}
+
+func (e *ejector) usesErrf(node ast.Node) bool {
+	found := false
+	ast.Inspect(node, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && e.isErrfPkg(ident) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func (e *ejector) isErrfPkg(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	pkgName, ok := e.info.Uses[ident].(*types.PkgName)
+	return ok && pkgName.Imported().Path() == errfPath
+}
+
+// errfObject returns errflow object name, referenced by selector (e.g. "IfError", "Std", "CheckInt").
+func (e *ejector) errfObject(expr ast.Expr) string {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return ""
+	}
+	obj := e.info.Uses[selector.Sel]
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != errfPath {
+		return ""
+	}
+	return obj.Name()
+}
+
+func (f *ejectFunc) eject() string {
+	if reason := f.parseScope(); reason != "" {
+		return reason
+	}
+	if reason := f.parseResults(); reason != "" {
+		return reason
+	}
+
+	reason := ""
+	visitList := func(list []ast.Stmt) {
+		for _, stmt := range list {
+			if reason == "" {
+				reason = f.ejectStmt(stmt)
+			}
+		}
+	}
+	ast.Inspect(f.fd.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			visitList(n.List)
+		case *ast.CaseClause:
+			visitList(n.Body)
+		case *ast.CommClause:
+			visitList(n.Body)
+		}
+		return reason == ""
+	})
+	if reason != "" {
+		return reason
+	}
+	f.removeOptionVars()
+
+	ast.Inspect(f.fd.Body, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		if ok && reason == "" && f.isErrfPkg(ident) && !f.isCovered(ident.Pos()) {
+			parent := f.source(ident)
+			if selector := f.enclosingSelector(ident); selector != nil {
+				parent = f.source(selector)
+			}
+			reason = fmt.Sprintf("unsupported %s usage at %s", parent, f.fset.Position(ident.Pos()))
+		}
+		return reason == ""
+	})
+	return reason
+}
+
+// change records behavior change, caused by conversion.
+func (f *ejectFunc) change(text string) {
+	for _, existing := range f.changes {
+		if existing == text {
+			return
+		}
+	}
+	f.changes = append(f.changes, text)
+}
+
+func (f *ejectFunc) enclosingSelector(ident *ast.Ident) *ast.SelectorExpr {
+	var result *ast.SelectorExpr
+	ast.Inspect(f.fd.Body, func(node ast.Node) bool {
+		if selector, ok := node.(*ast.SelectorExpr); ok && selector.X == ast.Expr(ident) {
+			result = selector
+		}
+		return result == nil
+	})
+	return result
+}
+
+func (f *ejectFunc) isCovered(pos token.Pos) bool {
+	for _, r := range f.covered {
+		if r[0] <= pos && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ejectFunc) replace(node ast.Node, text string) {
+	f.covered = append(f.covered, [2]token.Pos{node.Pos(), node.End()})
+	f.edits = append(f.edits, srcedit.Edit{Start: f.offset(node.Pos()), End: f.offset(node.End()), Text: text})
+}
+
+// remove deletes statement together with following whitespace.
+func (f *ejectFunc) remove(stmt ast.Stmt) {
+	end := f.offset(stmt.End())
+	for end < len(f.src) && strings.ContainsRune(" \t\r\n", rune(f.src[end])) {
+		end++
+	}
+	f.covered = append(f.covered, [2]token.Pos{stmt.Pos(), stmt.End()})
+	f.edits = append(f.edits, srcedit.Edit{Start: f.offset(stmt.Pos()), End: end})
+}
+
+// parseScope parses 'defer errf.IfError()...ThenAssignTo(&err)' statement.
+func (f *ejectFunc) parseScope() string {
+	const expected = "function should have 'defer errf.IfError()...ThenAssignTo(&err)' statement"
+	var deferStmt *ast.DeferStmt
+	for _, stmt := range f.fd.Body.List {
+		if s, ok := stmt.(*ast.DeferStmt); ok && f.errfObject(s.Call.Fun) == "ThenAssignTo" {
+			deferStmt = s
+			break
+		}
+	}
+	if deferStmt == nil || len(deferStmt.Call.Args) != 1 {
+		return expected
+	}
+	call := deferStmt.Call
+	selector := call.Fun.(*ast.SelectorExpr)
+	unary, ok := call.Args[0].(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return expected
+	}
+	errIdent, ok := unary.X.(*ast.Ident)
+	if !ok {
+		return expected
+	}
+	f.errName = errIdent.Name
+
+	var methods []*ast.CallExpr
+	expr := selector.X
+	for {
+		methodCall, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return expected
+		}
+		if f.errfObject(methodCall.Fun) == "IfError" && f.isErrfPkg(methodCall.Fun.(*ast.SelectorExpr).X) {
+			break
+		}
+		methodSelector, ok := methodCall.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return expected
+		}
+		methods = append([]*ast.CallExpr{methodCall}, methods...)
+		expr = methodSelector.X
+	}
+
+	for _, methodCall := range methods {
+		name := methodCall.Fun.(*ast.SelectorExpr).Sel.Name
+		if name == "Apply" {
+			for _, arg := range methodCall.Args {
+				opt, reason := f.parseOption(arg)
+				if reason != "" {
+					return reason
+				}
+				f.scope = append(f.scope, opt)
+			}
+		} else if optionName, ok := ifErrorMethods[name]; ok {
+			f.scope = append(f.scope, namedOptions[optionName])
+		} else {
+			return fmt.Sprintf("unsupported IfError() option %s", name)
+		}
+	}
+
+	f.remove(deferStmt)
+	return ""
+}
+
+// parseResults computes function return values used on error:
+// current values for named results and zero values for blank ones.
+func (f *ejectFunc) parseResults() string {
+	fields := f.fd.Type.Results.List
+	for i, field := range fields {
+		for j, name := range field.Names {
+			if i == len(fields)-1 && j == len(field.Names)-1 {
+				if name.Name != f.errName {
+					return fmt.Sprintf("'%s' should be the last function result", f.errName)
+				}
+				continue
+			}
+			if name.Name != "_" {
+				f.results = append(f.results, name.Name)
+				continue
+			}
+			zero, ok := f.zeroValue(f.info.TypeOf(field.Type))
+			if !ok {
+				return fmt.Sprintf("cannot create zero value for %s", f.source(field.Type))
+			}
+			f.results = append(f.results, zero)
+		}
+	}
+	return ""
+}
+
+func (f *ejectFunc) zeroValue(t types.Type) (string, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false", true
+		case u.Info()&types.IsString != 0:
+			return `""`, true
+		case u.Info()&types.IsNumeric != 0:
+			return "0", true
+		case u.Kind() == types.UnsafePointer:
+			return "nil", true
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		if _, isTypeParam := t.(*types.TypeParam); !isTypeParam {
+			return "nil", true
+		}
+	case *types.Struct, *types.Array:
+		if typeText, ok := f.typeString(t); ok {
+			return typeText + "{}", true
+		}
+		return "", false
+	}
+	typeText, ok := f.typeString(t)
+	return "*new(" + typeText + ")", ok
+}
+
+// typeString formats t using package names imported in the file.
+func (f *ejectFunc) typeString(t types.Type) (string, bool) {
+	ok := true
+	result := types.TypeString(t, func(p *types.Package) string {
+		if p == f.pkg {
+			return ""
+		}
+		for _, spec := range f.file.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == p.Path() {
+				if spec.Name != nil {
+					return spec.Name.Name
+				}
+				return p.Name()
+			}
+		}
+		ok = false
+		return p.Name()
+	})
+	return result, ok
+}
+
+// parseOption converts ErrflowOption expression.
+func (f *ejectFunc) parseOption(expr ast.Expr) (option, string) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		if def := f.localDefinition(ident); def != nil {
+			opt, reason := f.parseOption(def.Rhs[0])
+			if reason == "" {
+				f.optionVars[f.info.Uses[ident]] = def
+			}
+			return opt, reason
+		}
+	}
+	if name := f.errfObject(expr); name != "" {
+		if opt, ok := namedOptions[name]; ok {
+			return opt, ""
+		}
+		return nil, fmt.Sprintf("unsupported option %s", f.source(expr))
+	}
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, fmt.Sprintf("unsupported option %s", f.source(expr))
+	}
+	switch f.errfObject(call.Fun) {
+	case "WrapperFmtErrorw":
+		if len(call.Args) != 1 {
+			break
+		}
+		if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if msg, err := strconv.Unquote(lit.Value); err == nil {
+				format := strconv.Quote(strings.ReplaceAll(msg, "%", "%%") + ": %w")
+				return wrapperOption(func(err string) string {
+					return "fmt.Errorf(" + format + ", " + err + ")"
+				}), ""
+			}
+		}
+		msg := f.source(call.Args[0])
+		return wrapperOption(func(err string) string {
+			return `fmt.Errorf("%s: %w", ` + msg + ", " + err + ")"
+		}), ""
+	case "WrapperFmtErrorf":
+		if len(call.Args) == 0 || call.Ellipsis.IsValid() {
+			break
+		}
+		var args []string
+		for _, arg := range call.Args {
+			if f.errfObject(arg) == "OriginalErr" {
+				args = append(args, "")
+			} else {
+				args = append(args, f.source(arg))
+			}
+		}
+		return wrapperOption(func(err string) string {
+			var result []string
+			for _, arg := range args {
+				if arg == "" {
+					arg = err
+				}
+				result = append(result, arg)
+			}
+			return "fmt.Errorf(" + strings.Join(result, ", ") + ")"
+		}), ""
+	case "Wrapper":
+		if len(call.Args) != 1 {
+			break
+		}
+		fn := f.source(call.Args[0])
+		return wrapperOption(func(err string) string {
+			return fn + "(" + err + ")"
+		}), ""
+	}
+	return nil, fmt.Sprintf("unsupported option %s", f.source(expr))
+}
+
+// localDefinition returns 'ident := value' statement, which defines a local variable
+// in the function body.
+func (f *ejectFunc) localDefinition(ident *ast.Ident) *ast.AssignStmt {
+	obj, ok := f.info.Uses[ident].(*types.Var)
+	if !ok {
+		return nil
+	}
+	for _, stmt := range f.fd.Body.List {
+		assign, ok := stmt.(*ast.AssignStmt)
+		if ok && assign.Tok == token.DEFINE && len(assign.Lhs) == 1 && len(assign.Rhs) == 1 {
+			if lhs, ok := assign.Lhs[0].(*ast.Ident); ok && f.info.Defs[lhs] == obj {
+				return assign
+			}
+		}
+	}
+	return nil
+}
+
+// removeOptionVars removes definitions of option variables, which are not used anymore.
+func (f *ejectFunc) removeOptionVars() {
+	for obj, def := range f.optionVars {
+		used := false
+		ast.Inspect(f.fd.Body, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && f.info.Uses[ident] == obj && !f.isCovered(ident.Pos()) {
+				used = true
+			}
+			return !used
+		})
+		if !used {
+			f.remove(def)
+		}
+	}
+}
+
+// parseChain parses Errflow instance expression (e.g. 'errf.Io.With(...)') and returns its options.
+func (f *ejectFunc) parseChain(expr ast.Expr) ([]option, string) {
+	if f.isErrfPkg(expr) {
+		return nil, ""
+	}
+	if selector, ok := expr.(*ast.SelectorExpr); ok && f.isErrfPkg(selector.X) {
+		if _, ok := f.info.Uses[selector.Sel].(*types.Var); ok {
+			return nil, ""
+		}
+	}
+	if call, ok := expr.(*ast.CallExpr); ok {
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "With" && f.errfObject(selector) == "With" {
+			options, reason := f.parseChain(selector.X)
+			if reason != "" {
+				return nil, reason
+			}
+			for _, arg := range call.Args {
+				opt, reason := f.parseOption(arg)
+				if reason != "" {
+					return nil, reason
+				}
+				options = append(options, opt)
+			}
+			return options, ""
+		}
+	}
+	return nil, fmt.Sprintf("unsupported errflow instance %s", f.source(expr))
+}
+
+// checkCall is a parsed Check* call.
+type checkCall struct {
+	name    string
+	options optionSet
+	args    []ast.Expr
+	// typed is true for Check* functions returning value (e.g. CheckAny, Std.CheckInt).
+	typed bool
+}
+
+// parseCheck returns nil, if expr is not a Check* call.
+func (f *ejectFunc) parseCheck(expr ast.Expr) (*checkCall, string) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil, ""
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil, ""
+	}
+	name := f.errfObject(selector)
+	if !strings.HasPrefix(name, "Check") {
+		return nil, ""
+	}
+	chainOptions, reason := f.parseChain(selector.X)
+	if reason != "" {
+		return nil, reason
+	}
+	check := &checkCall{name: name, options: resolveOptions(chainOptions, f.scope), args: call.Args}
+	if f.isNetMethod(selector) {
+		f.change(fmt.Sprintf("errf.Net.%s is converted without classification of errors "+
+			"into *errf.NetTimeoutError and *errf.NetTemporaryError", name))
+	}
+
+	switch name {
+	case "CheckErr", "CheckDeferErr", "CheckDiscard":
+		if len(call.Args) == 1 {
+			return check, ""
+		}
+	case "CheckCondition", "CheckAssert":
+		if len(call.Args) >= 2 {
+			return check, ""
+		}
+	case "CheckAny":
+		check.typed = true
+		if len(call.Args) == 1 {
+			return check, ""
+		}
+	default:
+		signature, ok := f.info.TypeOf(selector).(*types.Signature)
+		if ok && signature.Params().Len() == 2 && signature.Results().Len() == 1 &&
+			isErrorType(signature.Params().At(1).Type()) &&
+			types.Identical(signature.Params().At(0).Type(), signature.Results().At(0).Type()) &&
+			len(call.Args) == 1 {
+			check.typed = true
+			return check, ""
+		}
+	}
+	return nil, fmt.Sprintf("unsupported %s call at %s", f.source(selector), f.fset.Position(call.Pos()))
+}
+
+// isNetMethod reports whether selector refers to a method of errf.Net table.
+func (f *ejectFunc) isNetMethod(selector *ast.SelectorExpr) bool {
+	fn, ok := f.info.Uses[selector.Sel].(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return false
+	}
+	named, ok := types.Unalias(recv.Type()).(*types.Named)
+	return ok && named.Obj().Name() == "NetErrflow"
+}
+
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// callResults returns results of multi-value call expression.
+func (f *ejectFunc) callResults(expr ast.Expr) *types.Tuple {
+	if _, ok := expr.(*ast.CallExpr); !ok {
+		return nil
+	}
+	tuple, _ := f.info.TypeOf(expr).(*types.Tuple)
+	return tuple
+}
+
+// wrap applies wrappers from options to err expression.
+func (f *ejectFunc) wrap(options optionSet, err string) string {
+	wrapped := options.wrap(err)
+	if wrapped != err {
+		f.imports["fmt"] = true
+	}
+	return wrapped
+}
+
+// onError returns statements, which return err (after processing it with options) from the function.
+func (f *ejectFunc) onError(options optionSet, err string) string {
+	var lines []string
+	wrapped := f.wrap(options, err)
+	if options.logStrategy == "Always" {
+		if wrapped != err {
+			lines = append(lines, err+" = "+wrapped)
+		}
+		lines = append(lines, fmt.Sprintf("log.Printf(\"error: %%v\", %s)", err))
+		f.imports["log"] = true
+		wrapped = err
+	}
+	lines = append(lines, "return "+strings.Join(append(append([]string{}, f.results...), wrapped), ", "))
+	return strings.Join(lines, "\n")
+}
+
+func (f *ejectFunc) ejectStmt(stmt ast.Stmt) string {
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		if call, ok := s.X.(*ast.CallExpr); ok && f.errfObject(call.Fun) == "Log" && len(call.Args) == 1 {
+			if _, isIdent := call.Args[0].(*ast.Ident); !isIdent {
+				return fmt.Sprintf("unsupported Log argument at %s", f.fset.Position(call.Pos()))
+			}
+			options, reason := f.parseChain(call.Fun.(*ast.SelectorExpr).X)
+			if reason != "" {
+				return reason
+			}
+			errText := f.source(call.Args[0])
+			f.imports["log"] = true
+			f.replace(s, fmt.Sprintf("if %s != nil {\nlog.Printf(\"error: %%v\", %s)\n}", errText, f.wrap(resolveOptions(options), errText)))
+			return ""
+		}
+		check, reason := f.parseCheck(s.X)
+		if check == nil {
+			return reason
+		}
+		text, reason := f.checkStmt(check)
+		if reason != "" {
+			return reason
+		}
+		f.replace(s, text)
+
+	case *ast.AssignStmt:
+		if len(s.Lhs) != 1 || len(s.Rhs) != 1 || (s.Tok != token.DEFINE && s.Tok != token.ASSIGN) {
+			return ""
+		}
+		rhs := s.Rhs[0]
+		var assertType ast.Expr
+		if typeAssert, ok := rhs.(*ast.TypeAssertExpr); ok && typeAssert.Type != nil {
+			rhs, assertType = typeAssert.X, typeAssert.Type
+		}
+		check, reason := f.parseCheck(rhs)
+		if check == nil || reason != "" {
+			return reason
+		}
+		if !check.typed {
+			return fmt.Sprintf("%s result is assigned at %s", check.name, f.fset.Position(s.Pos()))
+		}
+		results := f.callResults(check.args[0])
+		if results == nil || results.Len() != 2 || !isErrorType(results.At(1).Type()) {
+			return fmt.Sprintf("%s argument should be a (value, error) function call at %s", check.name, f.fset.Position(s.Pos()))
+		}
+		if check.name == "CheckAny" {
+			if assertType == nil || !types.Identical(f.info.TypeOf(assertType), results.At(0).Type()) {
+				return fmt.Sprintf("CheckAny result type doesn't match function result at %s", f.fset.Position(s.Pos()))
+			}
+		} else if assertType != nil {
+			return ""
+		}
+		f.replace(s, fmt.Sprintf("%s, %s %s %s\nif %s != nil {\n%s\n}",
+			f.source(s.Lhs[0]), f.errName, s.Tok, f.source(check.args[0]), f.errName, f.onError(check.options, f.errName)))
+
+	case *ast.ReturnStmt:
+		if len(s.Results) == 0 {
+			return ""
+		}
+		last, ok := s.Results[len(s.Results)-1].(*ast.SelectorExpr)
+		if !ok || last.Sel.Name != "IfOkReturnNil" {
+			return ""
+		}
+		check, reason := f.parseCheck(last.X)
+		if check == nil || reason != "" {
+			return reason
+		}
+		text, reason := f.checkStmt(check)
+		if reason != "" {
+			return reason
+		}
+		var results []string
+		for _, result := range s.Results[:len(s.Results)-1] {
+			results = append(results, f.source(result))
+		}
+		f.replace(s, text+"\nreturn "+strings.Join(append(results, "nil"), ", "))
+
+	case *ast.DeferStmt:
+		if check, reason := f.parseCheck(s.Call); check != nil || reason != "" {
+			if reason != "" {
+				return reason
+			}
+			if check.name != "CheckDeferErr" {
+				return fmt.Sprintf("unsupported deferred %s at %s", check.name, f.fset.Position(s.Pos()))
+			}
+			f.replace(s, f.deferCheck(check))
+			return ""
+		}
+		if f.errfObject(s.Call.Fun) == "LogDefer" && len(s.Call.Args) == 1 {
+			options, reason := f.parseChain(s.Call.Fun.(*ast.SelectorExpr).X)
+			if reason != "" {
+				return reason
+			}
+			f.imports["log"] = true
+			f.replace(s, fmt.Sprintf("defer func() {\nif closeErr := %s(); closeErr != nil {\nlog.Printf(\"error: %%v\", %s)\n}\n}()",
+				f.source(s.Call.Args[0]), f.wrap(resolveOptions(options), "closeErr")))
+			return ""
+		}
+		if selector, ok := s.Call.Fun.(*ast.SelectorExpr); ok {
+			if handleCall, ok := selector.X.(*ast.CallExpr); ok && f.errfObject(handleCall.Fun) == "Handle" {
+				text, reason := f.handler(selector.Sel.Name, s.Call.Args)
+				if reason != "" {
+					return reason
+				}
+				f.replace(s, text)
+			}
+		}
+	}
+	return ""
+}
+
+// checkStmt converts Check* call, which doesn't return a value.
+func (f *ejectFunc) checkStmt(check *checkCall) (string, string) {
+	errName := f.errName
+	onError := f.onError(check.options, errName)
+	switch check.name {
+	case "CheckErr":
+		return fmt.Sprintf("if %s = %s; %s != nil {\n%s\n}", errName, f.source(check.args[0]), errName, onError), ""
+	case "CheckDeferErr":
+		return fmt.Sprintf("if %s = %s(); %s != nil {\n%s\n}", errName, f.source(check.args[0]), errName, onError), ""
+	case "CheckCondition", "CheckAssert":
+		cond := f.source(check.args[0])
+		if check.name == "CheckAssert" {
+			cond = f.negate(check.args[0])
+		}
+		var args []string
+		for _, arg := range check.args[1:] {
+			args = append(args, f.source(arg))
+		}
+		f.imports["fmt"] = true
+		return fmt.Sprintf("if %s {\n%s = fmt.Errorf(%s)\n%s\n}", cond, errName, strings.Join(args, ", "), onError), ""
+	}
+
+	// CheckDiscard and typed checks with unused value.
+	results := f.callResults(check.args[0])
+	if results == nil || results.Len() != 2 || !isErrorType(results.At(1).Type()) {
+		return "", fmt.Sprintf("%s argument should be a (value, error) function call at %s", check.name, f.fset.Position(check.args[0].Pos()))
+	}
+	return fmt.Sprintf("if _, %s = %s; %s != nil {\n%s\n}", errName, f.source(check.args[0]), errName, onError), ""
+}
+
+var negatedOps = map[token.Token]token.Token{
+	token.EQL: token.NEQ, token.NEQ: token.EQL,
+	token.LSS: token.GEQ, token.GEQ: token.LSS,
+	token.GTR: token.LEQ, token.LEQ: token.GTR,
+}
+
+// negate returns negated condition expression.
+func (f *ejectFunc) negate(cond ast.Expr) string {
+	switch c := cond.(type) {
+	case *ast.BinaryExpr:
+		if op, ok := negatedOps[c.Op]; ok {
+			return f.source(c.X) + " " + op.String() + " " + f.source(c.Y)
+		}
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			return f.source(c.X)
+		}
+	case *ast.Ident, *ast.CallExpr, *ast.SelectorExpr, *ast.ParenExpr:
+		return "!" + f.source(c)
+	}
+	return "!(" + f.source(cond) + ")"
+}
+
+// call returns statement, which calls fn with args.
+// Function literals without parameters are inlined.
+func (f *ejectFunc) call(fn ast.Expr, args ...string) string {
+	if lit, ok := fn.(*ast.FuncLit); ok && lit.Type.Params.NumFields() == 0 && len(args) == 0 && len(lit.Body.List) > 0 {
+		start := lit.Body.List[0].Pos()
+		end := lit.Body.List[len(lit.Body.List)-1].End()
+		return string(f.src[f.offset(start):f.offset(end)])
+	}
+	return f.source(fn) + "(" + strings.Join(args, ", ") + ")"
+}
+
+// deferCheck converts 'defer errf.CheckDeferErr(closeFn)' into a deferred closure,
+// which combines close error with function error using return strategy.
+func (f *ejectFunc) deferCheck(check *checkCall) string {
+	const closeErr = "closeErr"
+	errName := f.errName
+	options := check.options
+
+	var lines []string
+	if wrapped := f.wrap(options, closeErr); wrapped != closeErr {
+		lines = append(lines, closeErr+" = "+wrapped)
+	}
+	if options.logStrategy == "Always" {
+		f.imports["log"] = true
+		lines = append(lines, `log.Printf("error: %v", closeErr)`)
+	}
+
+	switch options.returnStrategy {
+	case "Last":
+		if resolveOptions(f.scope).logStrategy == "IfSuppressed" {
+			f.imports["log"] = true
+			lines = append(lines, fmt.Sprintf("if %s != nil {\nlog.Printf(\"suppressed error: %%v\", %s)\n}", errName, errName))
+		}
+		lines = append(lines, errName+" = "+closeErr)
+	case "Wrapped":
+		f.imports["fmt"] = true
+		lines = append(lines, fmt.Sprintf("if %s == nil {\n%s = %s\n} else {\n%s = fmt.Errorf(\"%%w (also: %%s)\", %s, %s.Error())\n}",
+			errName, errName, closeErr, errName, errName, closeErr))
+	case "Combined":
+		f.imports["errors"] = true
+		f.change("ReturnCombined is converted to errors.Join, which formats error messages differently")
+		lines = append(lines, fmt.Sprintf("if %s == nil {\n%s = %s\n} else {\n%s = errors.Join(%s, %s)\n}",
+			errName, errName, closeErr, errName, errName, closeErr))
+	default:
+		combine := fmt.Sprintf("if %s == nil {\n%s = %s\n}", errName, errName, closeErr)
+		if options.logStrategy == "IfSuppressed" {
+			f.imports["log"] = true
+			combine += fmt.Sprintf(" else {\nlog.Printf(\"suppressed error: %%v\", %s)\n}", closeErr)
+		}
+		lines = append(lines, combine)
+	}
+
+	return fmt.Sprintf("defer func() {\nif %s := %s(); %s != nil {\n%s\n}\n}()",
+		closeErr, f.source(check.args[0]), closeErr, strings.Join(lines, "\n"))
+}
+
+var handlerArgCount = map[string]int{
+	"Always": 1, "OnErr": 1, "OnAnyErr": 1, "OnErrIs": 2, "OnErrAs": 1,
+	"OnSuccess": 1, "OnPanic": 1, "OnAnyPanic": 1, "OnAnyErrOrPanic": 1,
+}
+
+// handler converts 'defer errf.Handle().On...(...)' into a deferred closure.
+func (f *ejectFunc) handler(method string, args []ast.Expr) (string, string) {
+	if count, ok := handlerArgCount[method]; !ok || count != len(args) {
+		return "", fmt.Sprintf("unsupported Handle().%s handler", method)
+	}
+	errName := f.errName
+	fn := args[len(args)-1]
+	const repanic = "if r := recover(); r != nil {\n%s\npanic(r)\n}"
+
+	switch method {
+	case "OnErr", "OnAnyErr", "OnErrIs", "OnErrAs", "OnAnyErrOrPanic":
+		f.change(fmt.Sprintf("Handle().%s is converted to a check of returned error, "+
+			"which also includes errors returned directly and wrapped by IfError() options", method))
+	}
+
+	var body string
+	switch method {
+	case "Always":
+		if _, ok := fn.(*ast.FuncLit); ok {
+			return "defer " + f.source(fn) + "()", ""
+		}
+		return "defer " + f.call(fn), ""
+	case "OnErr":
+		body = fmt.Sprintf("if %s != nil {\n%s\n}", errName, f.call(fn, errName))
+	case "OnAnyErr":
+		body = fmt.Sprintf("if %s != nil {\n%s\n}", errName, f.call(fn))
+	case "OnErrIs":
+		f.imports["errors"] = true
+		body = fmt.Sprintf("if errors.Is(%s, %s) {\n%s\n}", errName, f.source(args[0]), f.call(fn))
+	case "OnErrAs":
+		signature, ok := f.info.TypeOf(args[0]).(*types.Signature)
+		if !ok || signature.Params().Len() != 1 {
+			return "", "unsupported Handle().OnErrAs callback"
+		}
+		typeText, ok := f.typeString(signature.Params().At(0).Type())
+		if !ok {
+			return "", "unsupported Handle().OnErrAs callback type"
+		}
+		f.imports["errors"] = true
+		body = fmt.Sprintf("var target %s\nif errors.As(%s, &target) {\n%s\n}", typeText, errName, f.call(fn, "target"))
+	case "OnSuccess":
+		body = "if r := recover(); r != nil {\npanic(r)\n}" + fmt.Sprintf("\nif %s == nil {\n%s\n}", errName, f.call(fn))
+	case "OnPanic":
+		body = fmt.Sprintf(repanic, f.call(fn, "r"))
+	case "OnAnyPanic":
+		body = fmt.Sprintf(repanic, f.call(fn))
+	case "OnAnyErrOrPanic":
+		body = fmt.Sprintf(repanic, f.call(fn)) + fmt.Sprintf("\nif %s != nil {\n%s\n}", errName, f.call(fn))
+	}
+	return "defer func() {\n" + body + "\n}()", ""
+}
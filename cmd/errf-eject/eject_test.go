@@ -0,0 +1,251 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ejectSource(t *testing.T, src string) (string, []string) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return "", nil
+	}
+	info := &types.Info{
+		Types:  map[ast.Expr]types.TypeAndValue{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Uses:   map[*ast.Ident]types.Object{},
+		Scopes: map[ast.Node]*types.Scope{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("test", fset, []*ast.File{file}, info)
+	if !assert.NoError(t, err) {
+		return "", nil
+	}
+	result, notes, err := ejectFile(fset, file, []byte(src), pkg, info, "")
+	assert.NoError(t, err)
+	return string(result), notes
+}
+
+func Test_Eject_Checks(t *testing.T) {
+	result, notes := ejectSource(t, `package test
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/serhiy-t/errf"
+)
+
+type config struct{}
+
+func load() (*config, error) {
+	return &config{}, nil
+}
+
+func parse(s string) (_ int, err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	errf.CheckAssert(len(s) > 0, "empty string")
+	errf.CheckErr(os.Remove(s))
+	cfg := errf.CheckAny(load()).(*config)
+	other := errf.Check(load())
+	_, _ = cfg, other
+	v := errf.Std.With(errf.WrapperFmtErrorw("parse")).CheckInt(strconv.Atoi(s))
+	return v, nil
+}
+
+func write(file *os.File, data []byte) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	return errf.CheckDiscard(file.Write(data)).IfOkReturnNil
+}
+`)
+
+	assert.Empty(t, notes)
+	assert.Equal(t, `package test
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+type config struct{}
+
+func load() (*config, error) {
+	return &config{}, nil
+}
+
+func parse(s string) (_ int, err error) {
+	if len(s) <= 0 {
+		err = fmt.Errorf("empty string")
+		return 0, err
+	}
+	if err = os.Remove(s); err != nil {
+		return 0, err
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	other, err := load()
+	if err != nil {
+		return 0, err
+	}
+	_, _ = cfg, other
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("parse: %w", err)
+	}
+	return v, nil
+}
+
+func write(file *os.File, data []byte) (err error) {
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+`, result)
+}
+
+func Test_Eject_StrategiesAndDefers(t *testing.T) {
+	result, notes := ejectSource(t, `package test
+
+import (
+	"io"
+
+	"github.com/serhiy-t/errf"
+)
+
+func copyData(w io.WriteCloser, r io.ReadCloser) (n int64, err error) {
+	errWrapper := errf.WrapperFmtErrorw("copy")
+	defer errf.IfError().LogIfSuppressed().Apply(errWrapper).ThenAssignTo(&err)
+
+	defer errf.With(errf.ReturnStrategyWrapped).CheckDeferErr(w.Close)
+	defer errf.CheckDeferErr(r.Close)
+	defer errf.Handle().OnErr(func(err error) { println(err.Error()) })
+	defer errf.Handle().OnAnyPanic(func() { println("panic") })
+
+	n = errf.Std.With(errf.LogStrategyAlways).CheckInt64(io.Copy(w, r))
+	return n, nil
+}
+`)
+
+	assert.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "copyData: Handle().OnErr is converted to a check of returned error")
+	assert.Equal(t, `package test
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+func copyData(w io.WriteCloser, r io.ReadCloser) (n int64, err error) {
+	defer func() {
+		if closeErr := w.Close(); closeErr != nil {
+			closeErr = fmt.Errorf("copy: %w", closeErr)
+			if err == nil {
+				err = closeErr
+			} else {
+				err = fmt.Errorf("%w (also: %s)", err, closeErr.Error())
+			}
+		}
+	}()
+	defer func() {
+		if closeErr := r.Close(); closeErr != nil {
+			closeErr = fmt.Errorf("copy: %w", closeErr)
+			if err == nil {
+				err = closeErr
+			} else {
+				log.Printf("suppressed error: %v", closeErr)
+			}
+		}
+	}()
+	defer func() {
+		if err != nil {
+			func(err error) { println(err.Error()) }(err)
+		}
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			println("panic")
+			panic(r)
+		}
+	}()
+
+	n, err = io.Copy(w, r)
+	if err != nil {
+		err = fmt.Errorf("copy: %w", err)
+		log.Printf("error: %v", err)
+		return n, err
+	}
+	return n, nil
+}
+`, result)
+}
+
+func Test_Eject_Unsupported(t *testing.T) {
+	src := `package test
+
+import (
+	"os"
+
+	"github.com/serhiy-t/errf"
+)
+
+func nested(path string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	println(errf.Std.CheckString(os.Getwd()))
+	return nil
+}
+
+func noScope(path string) error {
+	return errf.CheckErr(os.Remove(path)).IfOkReturnNil
+}
+
+func label(path string) (err error) {
+	defer errf.IfError().Apply(errf.Label("remove")).ThenAssignTo(&err)
+
+	errf.CheckErr(os.Remove(path))
+	return nil
+}
+`
+	result, notes := ejectSource(t, src)
+	assert.Equal(t, src, result)
+	assert.Len(t, notes, 3)
+	assert.Contains(t, notes[0], "skipping nested: unsupported errf.Std usage")
+	assert.Contains(t, notes[1], "skipping noScope: function should have 'defer errf.IfError()...ThenAssignTo(&err)' statement")
+	assert.Contains(t, notes[2], "skipping label: unsupported option errf.Label(\"remove\")")
+}
+
+func Test_Eject_NetChecksNote(t *testing.T) {
+	result, notes := ejectSource(t, `package test
+
+import (
+	"net"
+
+	"github.com/serhiy-t/errf"
+)
+
+func dial(addr string) (_ net.Conn, err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	conn := errf.Net.CheckConn(net.Dial("tcp", addr))
+	return conn, nil
+}
+`)
+
+	assert.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "dial: errf.Net.CheckConn is converted without classification of errors")
+	assert.NotContains(t, result, "errf")
+}
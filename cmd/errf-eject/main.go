@@ -0,0 +1,126 @@
+// Command errf-eject rewrites functions, which use errflow, to explicit Go error handling,
+// e.g. for contributing code to projects, which don't depend on errflow.
+//
+// Usage:
+//  errf-eject [-w] [-func name] [packages]
+//
+// By default, it prints a unified diff of proposed changes (dry-run mode).
+// Use -w flag to write changes to source files.
+//
+// Supported constructs:
+//  * 'defer errf.IfError()...ThenAssignTo(&err)' with return and log strategies,
+//    wrappers and Apply(...) options;
+//  * Check* functions in statement, assignment and 'return errf.CheckErr(...).IfOkReturnNil' positions;
+//  * 'defer errf.CheckDeferErr(closeFn)', converted to a deferred closure,
+//    which combines errors using return strategy;
+//  * 'defer errf.Handle()...' handlers, converted to deferred closures.
+//
+// Example:
+//  func example(filename string) (_ int, err error) {
+//  	defer errf.IfError().Apply(errf.WrapperFmtErrorw("example")).ThenAssignTo(&err)
+//
+//  	file := errf.Os.CheckFile(os.Open(filename))
+//  	defer errf.CheckDeferErr(file.Close)
+//  	// ...
+//  }
+// is converted to:
+//  func example(filename string) (_ int, err error) {
+//  	file, err := os.Open(filename)
+//  	if err != nil {
+//  		return 0, fmt.Errorf("example: %w", err)
+//  	}
+//  	defer func() {
+//  		if closeErr := file.Close(); closeErr != nil {
+//  			closeErr = fmt.Errorf("example: %w", closeErr)
+//  			if err == nil {
+//  				err = closeErr
+//  			}
+//  		}
+//  	}()
+//  	// ...
+//  }
+//
+// Differences from errflow behavior:
+//  * errors returned directly (e.g. 'return 0, err') are not replaced by deferred errors;
+//  * Handle() callbacks see errors processed with IfError() options and errors returned directly;
+//  * ReturnCombined is converted to errors.Join;
+//  * errf.Net.Check* functions don't classify errors into *errf.NetTimeoutError
+//    and *errf.NetTemporaryError;
+//  * errors are logged using standard log package.
+//
+// Differences, which apply to a converted function, are also reported to stderr.
+//
+// Functions with unsupported constructs are not converted and are reported to stderr.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serhiy-t/errf/internal/srcedit"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	writeFlag = flag.Bool("w", false, "write changes to source files instead of printing diff")
+	funcFlag  = flag.String("func", "", "convert only functions with this name")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: errf-eject [-w] [-func name] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	if err := run(patterns, *funcFlag, *writeFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "errf-eject: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(patterns []string, funcName string, write bool) error {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+	}, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			result, notes, err := ejectFile(pkg.Fset, file, src, pkg.Types, pkg.TypesInfo, funcName)
+			for _, note := range notes {
+				fmt.Fprintln(os.Stderr, note)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+			if string(result) == string(src) {
+				continue
+			}
+			if write {
+				if err := os.WriteFile(filename, result, 0644); err != nil {
+					return err
+				}
+			} else {
+				fmt.Print(srcedit.Diff(filename, src, result))
+			}
+		}
+	}
+	return nil
+}
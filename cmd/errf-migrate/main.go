@@ -0,0 +1,103 @@
+// Command errf-migrate rewrites functions with explicit 'if err != nil { return ..., err }'
+// checks to errflow.
+//
+// Usage:
+//  errf-migrate [-w] [-defer-close] [packages]
+//
+// By default, it prints a unified diff of proposed changes (dry-run mode).
+// Use -w flag to write changes to source files.
+//
+// Function is converted only if all its error variables are used in
+// convertible statements:
+//  v, err := call()
+//  if err != nil {
+//  	return <zero values>, err
+//  }
+//
+//  if err := call(); err != nil {
+//  	return <zero values>, fmt.Errorf("context: %w", err)
+//  }
+//
+//  defer func() {
+//  	if cerr := file.Close(); cerr != nil && err == nil {
+//  		err = cerr
+//  	}
+//  }()
+//
+// Other error returns in converted functions are sent to IfError() handler:
+//  return <values>, errf.CheckErr(fmt.Errorf("invalid value: %d", v)).IfOkReturnNil
+//
+// Functions, which are not converted for non-trivial reasons, are reported to stderr.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/serhiy-t/errf/internal/srcedit"
+	"golang.org/x/tools/go/packages"
+)
+
+var (
+	writeFlag      = flag.Bool("w", false, "write changes to source files instead of printing diff")
+	deferCloseFlag = flag.Bool("defer-close", false, "also convert 'defer x.Close()' to 'defer errf.CheckDeferErr(x.Close)' (changes behavior)")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: errf-migrate [-w] [-defer-close] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	if err := run(patterns, options{deferClose: *deferCloseFlag}, *writeFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "errf-migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(patterns []string, opts options, write bool) error {
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+	}, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			result, notes, err := migrateFile(pkg.Fset, file, src, pkg.Types, pkg.TypesInfo, opts)
+			for _, note := range notes {
+				fmt.Fprintln(os.Stderr, note)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", filename, err)
+			}
+			if string(result) == string(src) {
+				continue
+			}
+			if write {
+				if err := os.WriteFile(filename, result, 0644); err != nil {
+					return err
+				}
+			} else {
+				fmt.Print(srcedit.Diff(filename, src, result))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,700 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/serhiy-t/errf/internal/srcedit"
+	"github.com/serhiy-t/errf/internal/typedcheck"
+)
+
+const errfPath = "github.com/serhiy-t/errf"
+
+type options struct {
+	// deferClose enables conversion of 'defer x.Close()' into 'defer errf.CheckDeferErr(x.Close)'.
+	// Note: it changes behavior, since Close errors were ignored before conversion.
+	deferClose bool
+}
+
+// conversion replaces [start, end) source range with text.
+type conversion struct {
+	start  token.Pos
+	end    token.Pos
+	text   string
+	errVar *types.Var
+	// inPlace is true for conversions, which keep surrounding statement
+	// (e.g. direct error returns), so identifiers in it are still used.
+	inPlace bool
+}
+
+type migrator struct {
+	fset *token.FileSet
+	file *ast.File
+	src  []byte
+	pkg  *types.Package
+	info *types.Info
+	opts options
+
+	errfName string
+	edits    []srcedit.Edit
+	notes    []string
+}
+
+// migrateFile rewrites eligible functions in file to errflow
+// and returns resulting source. It returns src unmodified if nothing was converted.
+func migrateFile(fset *token.FileSet, file *ast.File, src []byte, pkg *types.Package, info *types.Info, opts options) ([]byte, []string, error) {
+	if pkg.Path() == errfPath {
+		// errflow itself can't be used in its own package.
+		return src, nil, nil
+	}
+	m := &migrator{fset: fset, file: file, src: src, pkg: pkg, info: info, opts: opts, errfName: "errf"}
+
+	hasErrfImport := false
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path == errfPath {
+			hasErrfImport = true
+			if spec.Name != nil {
+				m.errfName = spec.Name.Name
+			}
+		}
+	}
+
+	converted := false
+	for _, decl := range file.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Body != nil {
+			if m.migrateFunc(fd) {
+				converted = true
+			}
+		}
+	}
+	if !converted {
+		return src, m.notes, nil
+	}
+	result, err := srcedit.Apply(src, m.edits)
+	if err != nil {
+		return nil, m.notes, err
+	}
+	if !hasErrfImport {
+		if result, err = srcedit.AddImport(result, errfPath); err != nil {
+			return nil, m.notes, err
+		}
+	}
+	result, err = srcedit.RemoveUnusedImport(result, "fmt")
+	return result, m.notes, err
+}
+
+func (m *migrator) note(node ast.Node, format string, a ...interface{}) {
+	m.notes = append(m.notes, fmt.Sprintf("%s: %s", m.fset.Position(node.Pos()), fmt.Sprintf(format, a...)))
+}
+
+func (m *migrator) offset(pos token.Pos) int {
+	return m.fset.Position(pos).Offset
+}
+
+func (m *migrator) source(node ast.Node) string {
+	return string(m.src[m.offset(node.Pos()):m.offset(node.End())])
+}
+
+func (m *migrator) objectOf(ident *ast.Ident) types.Object {
+	if obj := m.info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return m.info.Uses[ident]
+}
+
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func (m *migrator) migrateFunc(fd *ast.FuncDecl) bool {
+	results := fd.Type.Results
+	if results == nil || len(results.List) == 0 {
+		return false
+	}
+	lastField := results.List[len(results.List)-1]
+	if !isErrorType(m.info.TypeOf(lastField.Type)) {
+		return false
+	}
+	if m.usesErrf(fd.Body) {
+		return false
+	}
+
+	var resultErr *types.Var
+	for _, field := range results.List {
+		for _, name := range field.Names {
+			if field == lastField && name == lastField.Names[len(lastField.Names)-1] && name.Name == "err" {
+				resultErr, _ = m.info.Defs[name].(*types.Var)
+			} else if name.Name != "_" {
+				m.note(fd, "skipping %s: function has named results", fd.Name.Name)
+				return false
+			}
+		}
+	}
+	if len(lastField.Names) > 0 && resultErr == nil {
+		m.note(fd, "skipping %s: error result should be named 'err'", fd.Name.Name)
+		return false
+	}
+
+	resultCount := 0
+	for _, field := range results.List {
+		if len(field.Names) == 0 {
+			resultCount++
+		} else {
+			resultCount += len(field.Names)
+		}
+	}
+
+	conversions := m.collectConversions(fd.Body, resultCount, resultErr)
+	if len(conversions) == 0 {
+		return false
+	}
+	if m.callsRecover(fd.Body) {
+		// recover() would swallow errflow panics, sent by Check* functions.
+		m.note(fd, "skipping %s: function calls recover()", fd.Name.Name)
+		return false
+	}
+	returns, reason := m.collectReturns(fd.Body, conversions, resultCount)
+	if reason != "" {
+		m.note(fd, "skipping %s: %s", fd.Name.Name, reason)
+		return false
+	}
+	conversions = append(conversions, returns...)
+	if reason := m.checkErrUses(fd, conversions, resultErr); reason != "" {
+		m.note(fd, "skipping %s: %s", fd.Name.Name, reason)
+		return false
+	}
+
+	if resultErr == nil {
+		var parts []string
+		for i, field := range results.List {
+			typeText := m.source(field.Type)
+			if i == len(results.List)-1 {
+				parts = append(parts, "err "+typeText)
+			} else {
+				parts = append(parts, "_ "+typeText)
+			}
+		}
+		m.edits = append(m.edits, srcedit.Edit{
+			Start: m.offset(results.Pos()),
+			End:   m.offset(results.End()),
+			Text:  "(" + strings.Join(parts, ", ") + ")",
+		})
+	}
+	bodyStart := m.offset(fd.Body.Lbrace) + 1
+	m.edits = append(m.edits, srcedit.Edit{
+		Start: bodyStart,
+		End:   bodyStart,
+		Text:  "\ndefer " + m.errfName + ".IfError().ThenAssignTo(&err)\n",
+	})
+	for _, c := range conversions {
+		m.edits = append(m.edits, srcedit.Edit{Start: m.offset(c.start), End: m.offset(c.end), Text: c.text})
+	}
+	return true
+}
+
+func (m *migrator) usesErrf(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok {
+			if pkgName, ok := m.info.Uses[ident].(*types.PkgName); ok && pkgName.Imported().Path() == errfPath {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// callsRecover reports whether body calls recover(), including function literals
+// (e.g. 'defer func() { if r := recover(); r != nil { ... } }()').
+func (m *migrator) callsRecover(body *ast.BlockStmt) bool {
+	found := false
+	ast.Inspect(body, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if ident, ok := call.Fun.(*ast.Ident); ok {
+				if builtin, ok := m.info.Uses[ident].(*types.Builtin); ok && builtin.Name() == "recover" {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// collectConversions finds convertible statements in body, excluding function literals.
+func (m *migrator) collectConversions(body *ast.BlockStmt, resultCount int, resultErr *types.Var) []conversion {
+	var conversions []conversion
+	visitList := func(list []ast.Stmt) {
+		for i := 0; i < len(list); i++ {
+			switch stmt := list[i].(type) {
+			case *ast.AssignStmt:
+				if i+1 < len(list) {
+					if ifStmt, ok := list[i+1].(*ast.IfStmt); ok {
+						if c, ok := m.matchAssignIf(stmt, ifStmt, resultCount); ok {
+							conversions = append(conversions, c)
+							i++
+						}
+					}
+				}
+			case *ast.IfStmt:
+				if c, ok := m.matchIfInit(stmt, resultCount); ok {
+					conversions = append(conversions, c)
+				}
+			case *ast.DeferStmt:
+				if c, ok := m.matchDeferClose(stmt, resultErr); ok {
+					conversions = append(conversions, c)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BlockStmt:
+			visitList(n.List)
+		case *ast.CaseClause:
+			visitList(n.Body)
+		case *ast.CommClause:
+			visitList(n.Body)
+		}
+		return true
+	})
+	return conversions
+}
+
+// collectReturns converts direct error returns in body, which are not a part of conversions,
+// into 'return <values>, errf.CheckErr(<error>).IfOkReturnNil',
+// so all errors are processed by IfError() handler.
+// It returns non-empty reason, if some return can't be converted.
+func (m *migrator) collectReturns(body *ast.BlockStmt, conversions []conversion, resultCount int) ([]conversion, string) {
+	var returns []conversion
+	reason := ""
+	ast.Inspect(body, func(node ast.Node) bool {
+		if reason != "" {
+			return false
+		}
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, c := range conversions {
+				if c.start <= n.Pos() && n.Pos() < c.end {
+					return false
+				}
+			}
+			if len(n.Results) == 0 {
+				return false
+			}
+			if len(n.Results) != resultCount {
+				reason = fmt.Sprintf("error is returned directly at %s", m.fset.Position(n.Pos()))
+				return false
+			}
+			last := n.Results[resultCount-1]
+			if m.info.Types[last].IsNil() {
+				return false
+			}
+			returns = append(returns, conversion{
+				start:   last.Pos(),
+				end:     last.End(),
+				text:    m.errfName + ".CheckErr(" + m.source(last) + ").IfOkReturnNil",
+				inPlace: true,
+			})
+			return false
+		}
+		return true
+	})
+	return returns, reason
+}
+
+// errCheck matches 'err != nil' condition and returns err variable.
+func (m *migrator) errCheck(cond ast.Expr) *types.Var {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return nil
+	}
+	ident, ok := binary.X.(*ast.Ident)
+	if !ok || !m.info.Types[binary.Y].IsNil() {
+		return nil
+	}
+	errVar, ok := m.objectOf(ident).(*types.Var)
+	if !ok || !isErrorType(errVar.Type()) {
+		return nil
+	}
+	return errVar
+}
+
+// matchReturn matches 'return <zero values>, err' and 'return <zero values>, fmt.Errorf("msg: %w", err)'.
+// It returns wrapper message for the latter.
+func (m *migrator) matchReturn(body *ast.BlockStmt, errVar *types.Var, resultCount int) (wrapMsg string, ok bool) {
+	if len(body.List) != 1 {
+		return "", false
+	}
+	ret, ok := body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != resultCount {
+		return "", false
+	}
+	for _, result := range ret.Results[:resultCount-1] {
+		if !m.isZero(result) {
+			return "", false
+		}
+	}
+
+	last := ret.Results[resultCount-1]
+	if ident, ok := last.(*ast.Ident); ok {
+		return "", m.objectOf(ident) == errVar
+	}
+
+	call, ok := last.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || !m.isFunc(call.Fun, "fmt", "Errorf") {
+		return "", false
+	}
+	formatLit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || formatLit.Kind != token.STRING {
+		return "", false
+	}
+	format, err := strconv.Unquote(formatLit.Value)
+	if err != nil || !strings.HasSuffix(format, ": %w") {
+		return "", false
+	}
+	msg := strings.TrimSuffix(format, ": %w")
+	if strings.Contains(msg, "%") {
+		return "", false
+	}
+	ident, ok := call.Args[1].(*ast.Ident)
+	if !ok || m.objectOf(ident) != errVar {
+		return "", false
+	}
+	return msg, true
+}
+
+func (m *migrator) isFunc(expr ast.Expr, pkgPath, name string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := m.info.Uses[selector.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == pkgPath && fn.Name() == name
+}
+
+func (m *migrator) isZero(expr ast.Expr) bool {
+	tv := m.info.Types[expr]
+	if tv.IsNil() {
+		return true
+	}
+	if tv.Value != nil {
+		switch tv.Value.Kind() {
+		case constant.Bool:
+			return !constant.BoolVal(tv.Value)
+		case constant.String:
+			return constant.StringVal(tv.Value) == ""
+		case constant.Int, constant.Float, constant.Complex:
+			return constant.Sign(tv.Value) == 0
+		}
+		return false
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	return ok && len(lit.Elts) == 0
+}
+
+// callResults returns result types of call expression.
+func (m *migrator) callResults(call *ast.CallExpr) []types.Type {
+	switch t := m.info.TypeOf(call).(type) {
+	case nil:
+		return nil
+	case *types.Tuple:
+		var result []types.Type
+		for i := 0; i < t.Len(); i++ {
+			result = append(result, t.At(i).Type())
+		}
+		return result
+	default:
+		return []types.Type{t}
+	}
+}
+
+// checkCall returns Check* expression for call, which returns (value, error) or error.
+func (m *migrator) checkCall(call *ast.CallExpr, discardValue bool, wrapMsg string) (string, bool) {
+	callText := m.source(call)
+	results := m.callResults(call)
+
+	withWrapper := ""
+	if wrapMsg != "" {
+		withWrapper = fmt.Sprintf(".With(%s.WrapperFmtErrorw(%q))", m.errfName, wrapMsg)
+	}
+
+	switch {
+	case len(results) == 1 && isErrorType(results[0]):
+		return m.errfName + withWrapper + ".CheckErr(" + callText + ")", true
+	case len(results) == 2 && isErrorType(results[1]) && discardValue:
+		return m.errfName + withWrapper + ".CheckDiscard(" + callText + ")", true
+	case len(results) == 2 && isErrorType(results[1]):
+		if table, method := typedcheck.Lookup(results[0]); table != "" {
+			return m.errfName + "." + table + withWrapper + "." + method + "(" + callText + ")", true
+		}
+		if withWrapper == "" {
+			return m.errfName + ".Check(" + callText + ")", true
+		}
+		if types.IsInterface(results[0]) {
+			// CheckAny(...).(Interface) would panic for nil interface values.
+			return "", false
+		}
+		typeText, ok := m.typeString(results[0])
+		if !ok {
+			return "", false
+		}
+		return m.errfName + withWrapper + ".CheckAny(" + callText + ").(" + typeText + ")", true
+	}
+	return "", false
+}
+
+// typeString formats t using package names imported in the file.
+func (m *migrator) typeString(t types.Type) (string, bool) {
+	ok := true
+	result := types.TypeString(t, func(p *types.Package) string {
+		if p == m.pkg {
+			return ""
+		}
+		for _, spec := range m.file.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == p.Path() {
+				if spec.Name != nil {
+					return spec.Name.Name
+				}
+				return p.Name()
+			}
+		}
+		ok = false
+		return p.Name()
+	})
+	return result, ok
+}
+
+// matchAssignIf matches:
+//  v, err := call()
+//  if err != nil {
+//  	return <zero values>, err
+//  }
+func (m *migrator) matchAssignIf(assign *ast.AssignStmt, ifStmt *ast.IfStmt, resultCount int) (conversion, bool) {
+	if ifStmt.Init != nil || ifStmt.Else != nil || len(assign.Rhs) != 1 || len(assign.Lhs) > 2 {
+		return conversion{}, false
+	}
+	if assign.Tok != token.DEFINE && assign.Tok != token.ASSIGN {
+		return conversion{}, false
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return conversion{}, false
+	}
+	errIdent, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+	if !ok {
+		return conversion{}, false
+	}
+	errVar := m.errCheck(ifStmt.Cond)
+	if errVar == nil || m.objectOf(errIdent) != errVar {
+		return conversion{}, false
+	}
+	wrapMsg, ok := m.matchReturn(ifStmt.Body, errVar, resultCount)
+	if !ok {
+		return conversion{}, false
+	}
+
+	var text string
+	if len(assign.Lhs) == 1 {
+		text, ok = m.checkCall(call, false, wrapMsg)
+	} else {
+		valueIdent, isIdent := assign.Lhs[0].(*ast.Ident)
+		discard := isIdent && valueIdent.Name == "_"
+		text, ok = m.checkCall(call, discard, wrapMsg)
+		if ok && !discard {
+			tok := token.ASSIGN
+			if assign.Tok == token.DEFINE && isIdent && m.info.Defs[valueIdent] != nil {
+				tok = token.DEFINE
+			}
+			text = m.source(assign.Lhs[0]) + " " + tok.String() + " " + text
+		}
+	}
+	if !ok {
+		return conversion{}, false
+	}
+	return conversion{start: assign.Pos(), end: ifStmt.End(), text: text, errVar: errVar}, true
+}
+
+// matchIfInit matches:
+//  if err := call(); err != nil {
+//  	return <zero values>, err
+//  }
+func (m *migrator) matchIfInit(ifStmt *ast.IfStmt, resultCount int) (conversion, bool) {
+	assign, ok := ifStmt.Init.(*ast.AssignStmt)
+	if !ok || ifStmt.Else != nil || len(assign.Rhs) != 1 || len(assign.Lhs) > 2 {
+		return conversion{}, false
+	}
+	if len(assign.Lhs) == 2 {
+		if valueIdent, ok := assign.Lhs[0].(*ast.Ident); !ok || valueIdent.Name != "_" {
+			return conversion{}, false
+		}
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return conversion{}, false
+	}
+	errIdent, ok := assign.Lhs[len(assign.Lhs)-1].(*ast.Ident)
+	if !ok {
+		return conversion{}, false
+	}
+	errVar := m.errCheck(ifStmt.Cond)
+	if errVar == nil || m.objectOf(errIdent) != errVar {
+		return conversion{}, false
+	}
+	wrapMsg, ok := m.matchReturn(ifStmt.Body, errVar, resultCount)
+	if !ok {
+		return conversion{}, false
+	}
+	text, ok := m.checkCall(call, len(assign.Lhs) == 2, wrapMsg)
+	if !ok {
+		return conversion{}, false
+	}
+	return conversion{start: ifStmt.Pos(), end: ifStmt.End(), text: text, errVar: errVar}, true
+}
+
+// matchDeferClose matches:
+//  defer func() {
+//  	if cerr := x.Close(); cerr != nil && err == nil {
+//  		err = cerr
+//  	}
+//  }()
+// and, if enabled in options:
+//  defer x.Close()
+func (m *migrator) matchDeferClose(deferStmt *ast.DeferStmt, resultErr *types.Var) (conversion, bool) {
+	if m.opts.deferClose && len(deferStmt.Call.Args) == 0 && m.isCloseMethod(deferStmt.Call.Fun) {
+		return conversion{
+			start: deferStmt.Pos(),
+			end:   deferStmt.End(),
+			text:  "defer " + m.errfName + ".CheckDeferErr(" + m.source(deferStmt.Call.Fun) + ")",
+		}, true
+	}
+
+	funcLit, ok := deferStmt.Call.Fun.(*ast.FuncLit)
+	if !ok || resultErr == nil || len(deferStmt.Call.Args) != 0 || len(funcLit.Body.List) != 1 {
+		return conversion{}, false
+	}
+	ifStmt, ok := funcLit.Body.List[0].(*ast.IfStmt)
+	if !ok || ifStmt.Else != nil || len(ifStmt.Body.List) != 1 {
+		return conversion{}, false
+	}
+	init, ok := ifStmt.Init.(*ast.AssignStmt)
+	if !ok || init.Tok != token.DEFINE || len(init.Lhs) != 1 || len(init.Rhs) != 1 {
+		return conversion{}, false
+	}
+	closeCall, ok := init.Rhs[0].(*ast.CallExpr)
+	if !ok || len(closeCall.Args) != 0 || !m.isCloseMethod(closeCall.Fun) {
+		return conversion{}, false
+	}
+	cerrIdent, ok := init.Lhs[0].(*ast.Ident)
+	if !ok {
+		return conversion{}, false
+	}
+	cerrVar := m.objectOf(cerrIdent)
+
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	if !ok || cond.Op != token.LAND {
+		return conversion{}, false
+	}
+	if !(m.isNilCheck(cond.X, cerrVar, token.NEQ) && m.isNilCheck(cond.Y, resultErr, token.EQL)) &&
+		!(m.isNilCheck(cond.X, resultErr, token.EQL) && m.isNilCheck(cond.Y, cerrVar, token.NEQ)) {
+		return conversion{}, false
+	}
+
+	assign, ok := ifStmt.Body.List[0].(*ast.AssignStmt)
+	if !ok || assign.Tok != token.ASSIGN || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return conversion{}, false
+	}
+	lhs, lhsOk := assign.Lhs[0].(*ast.Ident)
+	rhs, rhsOk := assign.Rhs[0].(*ast.Ident)
+	if !lhsOk || !rhsOk || m.objectOf(lhs) != resultErr || m.objectOf(rhs) != cerrVar {
+		return conversion{}, false
+	}
+
+	return conversion{
+		start: deferStmt.Pos(),
+		end:   deferStmt.End(),
+		text:  "defer " + m.errfName + ".CheckDeferErr(" + m.source(closeCall.Fun) + ")",
+	}, true
+}
+
+func (m *migrator) isCloseMethod(expr ast.Expr) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok || selector.Sel.Name != "Close" {
+		return false
+	}
+	signature, ok := m.info.TypeOf(selector).(*types.Signature)
+	return ok && signature.Params().Len() == 0 &&
+		signature.Results().Len() == 1 && isErrorType(signature.Results().At(0).Type())
+}
+
+func (m *migrator) isNilCheck(expr ast.Expr, v types.Object, op token.Token) bool {
+	binary, ok := expr.(*ast.BinaryExpr)
+	if !ok || binary.Op != op {
+		return false
+	}
+	ident, ok := binary.X.(*ast.Ident)
+	return ok && m.objectOf(ident) == v && m.info.Types[binary.Y].IsNil()
+}
+
+// checkErrUses verifies that error variables are only used in converted statements
+// and that 'err' name is available for the error result.
+func (m *migrator) checkErrUses(fd *ast.FuncDecl, conversions []conversion, resultErr *types.Var) string {
+	errVars := map[types.Object]bool{}
+	for _, c := range conversions {
+		if c.errVar != nil {
+			errVars[c.errVar] = true
+		}
+	}
+	converted := func(pos token.Pos) bool {
+		for _, c := range conversions {
+			if !c.inPlace && c.start <= pos && pos < c.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	reason := ""
+	ast.Inspect(fd.Body, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Ident)
+		if !ok || reason != "" || converted(ident.Pos()) {
+			return reason == ""
+		}
+		obj := m.objectOf(ident)
+		if obj == nil {
+			return true
+		}
+		if errVars[obj] {
+			reason = fmt.Sprintf("%s is used outside of converted checks at %s", ident.Name, m.fset.Position(ident.Pos()))
+		} else if obj == types.Object(resultErr) && resultErr != nil {
+			reason = fmt.Sprintf("err result is used at %s", m.fset.Position(ident.Pos()))
+		}
+		return true
+	})
+	if reason != "" {
+		return reason
+	}
+
+	if resultErr == nil {
+		// Function-level 'err' would conflict with the new named result.
+		for _, node := range []ast.Node{fd.Type, fd.Body} {
+			if scope := m.info.Scopes[node]; scope != nil {
+				if obj := scope.Lookup("err"); obj != nil && !errVars[obj] {
+					return "'err' name is already used"
+				}
+			}
+		}
+	}
+	return ""
+}
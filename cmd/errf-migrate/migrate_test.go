@@ -0,0 +1,376 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func migrateSource(t *testing.T, src string, opts options) (string, []string) {
+	return migratePackageSource(t, "test", src, opts)
+}
+
+func migratePackageSource(t *testing.T, path string, src string, opts options) (string, []string) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "test.go", src, parser.ParseComments)
+	if !assert.NoError(t, err) {
+		return "", nil
+	}
+	info := &types.Info{
+		Types:  map[ast.Expr]types.TypeAndValue{},
+		Defs:   map[*ast.Ident]types.Object{},
+		Uses:   map[*ast.Ident]types.Object{},
+		Scopes: map[ast.Node]*types.Scope{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, info)
+	if !assert.NoError(t, err) {
+		return "", nil
+	}
+	result, notes, err := migrateFile(fset, file, []byte(src), pkg, info, opts)
+	assert.NoError(t, err)
+	return string(result), notes
+}
+
+func Test_Migrate_TypedChecks(t *testing.T) {
+	result, notes := migrateSource(t, `package test
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func parse(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.Open(s)
+	if err != nil {
+		return 0, fmt.Errorf("open: %w", err)
+	}
+	if _, err := file.Write(nil); err != nil {
+		return 0, err
+	}
+	return v, nil
+}
+
+func write(path string) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	return nil
+}
+`, options{})
+
+	assert.Empty(t, notes)
+	assert.Equal(t, `package test
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/serhiy-t/errf"
+)
+
+func parse(s string) (_ int, err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	v := errf.Std.CheckInt(strconv.Atoi(s))
+	file := errf.Os.With(errf.WrapperFmtErrorw("open")).CheckFile(os.Open(s))
+	errf.CheckDiscard(file.Write(nil))
+	return v, nil
+}
+
+func write(path string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	file := errf.Os.CheckFile(os.Create(path))
+	defer errf.CheckDeferErr(file.Close)
+	return nil
+}
+`, result)
+}
+
+func Test_Migrate_CheckErrAndCheckAny(t *testing.T) {
+	result, notes := migrateSource(t, `package test
+
+import (
+	"fmt"
+	"os"
+)
+
+type config struct{}
+
+func load() (*config, error) {
+	return &config{}, nil
+}
+
+func run(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+	other, err := load()
+	if err != nil {
+		return fmt.Errorf("other: %w", err)
+	}
+	_, _ = cfg, other
+	return nil
+}
+`, options{})
+
+	assert.Empty(t, notes)
+	assert.Equal(t, `package test
+
+import (
+	"os"
+
+	"github.com/serhiy-t/errf"
+)
+
+type config struct{}
+
+func load() (*config, error) {
+	return &config{}, nil
+}
+
+func run(path string) (err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	errf.CheckErr(os.Remove(path))
+	cfg := errf.Check(load())
+	other := errf.With(errf.WrapperFmtErrorw("other")).CheckAny(load()).(*config)
+	_, _ = cfg, other
+	return nil
+}
+`, result)
+}
+
+func Test_Migrate_DeferClose(t *testing.T) {
+	src := `package test
+
+import "os"
+
+func create(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return nil
+}
+`
+	result, _ := migrateSource(t, src, options{})
+	assert.Contains(t, result, "\tdefer file.Close()\n")
+
+	result, _ = migrateSource(t, src, options{deferClose: true})
+	assert.Contains(t, result, "\tdefer errf.CheckDeferErr(file.Close)\n")
+}
+
+func Test_Migrate_SkipsIneligible(t *testing.T) {
+	src := `package test
+
+import (
+	"errors"
+	"os"
+)
+
+func usedOutside(path string) error {
+	err := os.Remove(path)
+	if err != nil {
+		return err
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return nil
+}
+
+func namedResult(path string) (n int, err error) {
+	if err := os.Remove(path); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func noError() int {
+	return 0
+}
+
+func nonZeroReturn(path string) (int, error) {
+	if err := os.Remove(path); err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+func closure(path string) error {
+	fn := func() error {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		return nil
+	}
+	return fn()
+}
+`
+	result, notes := migrateSource(t, src, options{})
+	assert.Equal(t, src, result)
+	assert.Len(t, notes, 2)
+	assert.Contains(t, notes[0], "skipping usedOutside: err is used outside of converted checks")
+	assert.Contains(t, notes[1], "skipping namedResult: function has named results")
+}
+
+func Test_Migrate_SkipsRecover(t *testing.T) {
+	src := `package test
+
+import (
+	"fmt"
+	"os"
+)
+
+func recovering(name string) (int, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Println("recovered:", r)
+		}
+	}()
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return 1, nil
+}
+`
+	result, notes := migrateSource(t, src, options{})
+	assert.Equal(t, src, result)
+	assert.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "skipping recovering: function calls recover()")
+}
+
+func Test_Migrate_SingleImport(t *testing.T) {
+	result, _ := migrateSource(t, `package test
+
+import "os"
+
+func remove(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return nil
+}
+`, options{})
+
+	assert.Contains(t, result, "import (\n\t\"os\"\n\n\t\"github.com/serhiy-t/errf\"\n)\n")
+}
+
+func Test_Migrate_DirectReturns(t *testing.T) {
+	result, notes := migrateSource(t, `package test
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func parse(s string) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("negative: %d", v)
+	}
+	return v, nil
+}
+
+func multiValueReturn(s string) (int, error) {
+	if err := os.Remove(s); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(s)
+}
+`, options{})
+
+	assert.Len(t, notes, 1)
+	assert.Contains(t, notes[0], "skipping multiValueReturn: error is returned directly at test.go:24:2")
+	assert.Equal(t, `package test
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/serhiy-t/errf"
+)
+
+func parse(s string) (_ int, err error) {
+	defer errf.IfError().ThenAssignTo(&err)
+
+	v := errf.Std.CheckInt(strconv.Atoi(s))
+	if v < 0 {
+		return 0, errf.CheckErr(fmt.Errorf("negative: %d", v)).IfOkReturnNil
+	}
+	return v, nil
+}
+
+func multiValueReturn(s string) (int, error) {
+	if err := os.Remove(s); err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(s)
+}
+`, result)
+}
+
+func Test_Migrate_NetTypesUseGenericCheck(t *testing.T) {
+	result, notes := migrateSource(t, `package test
+
+import "net"
+
+func dial(addr string) (net.Conn, error) {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+`, options{})
+
+	assert.Empty(t, notes)
+	assert.Contains(t, result, "\tconn := errf.Check(net.Dial(\"tcp\", addr))\n")
+}
+
+func Test_Migrate_SkipsErrfPackage(t *testing.T) {
+	src := `package errf
+
+import "os"
+
+func remove(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return nil
+}
+`
+	result, notes := migratePackageSource(t, errfPath, src, options{})
+	assert.Equal(t, src, result)
+	assert.Empty(t, notes)
+}
@@ -0,0 +1,26 @@
+// Command errf-vet runs errflow static analyzers.
+//
+// Usage:
+//  errf-vet [-fix] [packages]
+// or as a go vet tool:
+//  go vet -vettool=$(which errf-vet) [packages]
+//
+// Analyzers:
+//  * deferclose: reports ignored errors from deferred Close/Flush/Sync and Write calls
+//    on io.Writer values;
+//  * modernize: reports CheckAny type assertions, which can be replaced with typed checks
+//    or never succeed, and errors returned directly from functions with IfError() handler.
+package main
+
+import (
+	"github.com/serhiy-t/errf/analysis/deferclose"
+	"github.com/serhiy-t/errf/analysis/modernize"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(
+		deferclose.Analyzer,
+		modernize.Analyzer,
+	)
+}
@@ -0,0 +1,225 @@
+package errf
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+// IndexError is an error produced by collection helpers (Map, Filter, Reduce, ParallelMap).
+// It annotates original error with an index of an element, which caused it.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d: %s", e.Index, e.Err.Error())
+}
+
+// Format implements fmt.Formatter.
+func (e *IndexError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*IndexError) errfFormatted() {}
+
+// Unwrap returns original error.
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// Map calls fn for each element of in and returns a slice of results.
+//
+// First error returned by fn is wrapped in *IndexError and sent
+// to IfError() handler for processing.
+//
+// Example:
+//  func parseAll(values []string) (result []int, err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	return errf.Map(values, strconv.Atoi), nil
+//  }
+func Map[T, U any](in []T, fn func(T) (U, error)) []U {
+	result := make([]U, 0, len(in))
+	for idx, value := range in {
+		mapped, err := fn(value)
+		if err != nil {
+			DefaultErrflow.ImplementCheck(nil, &IndexError{Index: idx, Err: err})
+		}
+		result = append(result, mapped)
+	}
+	return result
+}
+
+// Filter returns a slice of elements of in, for which fn returns true.
+//
+// First error returned by fn is wrapped in *IndexError and sent
+// to IfError() handler for processing.
+func Filter[T any](in []T, fn func(T) (bool, error)) []T {
+	var result []T
+	for idx, value := range in {
+		keep, err := fn(value)
+		if err != nil {
+			DefaultErrflow.ImplementCheck(nil, &IndexError{Index: idx, Err: err})
+		}
+		if keep {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// Reduce calls fn for each element of in, passing result of a previous call
+// (or initial for the first element), and returns result of the last call.
+//
+// First error returned by fn is wrapped in *IndexError and sent
+// to IfError() handler for processing.
+func Reduce[T, A any](in []T, initial A, fn func(A, T) (A, error)) A {
+	result := initial
+	for idx, value := range in {
+		var err error
+		result, err = fn(result, value)
+		if err != nil {
+			DefaultErrflow.ImplementCheck(nil, &IndexError{Index: idx, Err: err})
+		}
+	}
+	return result
+}
+
+// ParallelErrflow configures ParallelMapWith.
+//
+// Go methods can't have type parameters, so configuration is created
+// using errf.Parallel.With(...) receiver and passed to ParallelMapWith.
+//
+// Example:
+//  errf.ParallelMapWith(errf.Parallel.With(errf.WrapperFmtErrorw("fetch")).StopOnErr(), urls, 8, fetch)
+type ParallelErrflow struct {
+	errflow   *Errflow
+	stopOnErr bool
+}
+
+// Parallel is a default ParallelMap configuration.
+var Parallel = ParallelErrflow{errflow: DefaultErrflow}
+
+// With implements Errflow.With(...) for ParallelMapWith.
+//
+// Options are applied to errors sent to IfError() handler.
+func (pf ParallelErrflow) With(options ...ErrflowOption) ParallelErrflow {
+	return ParallelErrflow{errflow: pf.errflow.With(options...), stopOnErr: pf.stopOnErr}
+}
+
+// StopOnErr configures ParallelMapWith to stop scheduling new elements
+// for processing after the first error.
+// Elements, which are already being processed, are not interrupted.
+func (pf ParallelErrflow) StopOnErr() ParallelErrflow {
+	return ParallelErrflow{errflow: pf.errflow, stopOnErr: true}
+}
+
+// WorkerPanic is a panic value used by ParallelMap, when fn panics in a worker goroutine.
+type WorkerPanic struct {
+	// Value is an original value passed to panic(...) in a worker goroutine.
+	Value interface{}
+	// Stack is a stack trace of a worker goroutine at the moment of panic.
+	Stack []byte
+}
+
+func (p *WorkerPanic) String() string {
+	return fmt.Sprintf("panic in ParallelMap worker: %v\n\n%s", p.Value, p.Stack)
+}
+
+// ParallelMap is same as Map, but it calls fn concurrently
+// in at most workers goroutines.
+// If workers <= 0, runtime.GOMAXPROCS(0) goroutines are used.
+//
+// All errors returned by fn are wrapped in *IndexError and sent to IfError() handler,
+// which combines them according to return strategy, in order of element indexes.
+//
+// If fn panics, ParallelMap waits for all goroutines to finish and
+// then panics with *WorkerPanic in the caller goroutine.
+//
+// Use ParallelMapWith to configure errors processing.
+//
+// Example:
+//  func fetchAll(urls []string) (pages []*Page, err error) {
+//  	defer errf.IfError().ReturnCombined().ThenAssignTo(&err)
+//
+//  	return errf.ParallelMap(urls, 8, fetch), nil
+//  }
+func ParallelMap[T, U any](in []T, workers int, fn func(T) (U, error)) []U {
+	return ParallelMapWith(Parallel, in, workers, fn)
+}
+
+// ParallelMapWith is same as ParallelMap, but uses configuration from pf
+// (see errf.Parallel).
+func ParallelMapWith[T, U any](pf ParallelErrflow, in []T, workers int, fn func(T) (U, error)) []U {
+	ef := pf.errflow
+	if ef == nil {
+		ef = DefaultErrflow
+	}
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(in) {
+		workers = len(in)
+	}
+
+	result := make([]U, len(in))
+	errs := make([]error, len(in))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	nextIdx := 0
+	failed := false
+	var panicObj interface{}
+
+	nextFn := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if nextIdx >= len(in) || (failed && pf.stopOnErr) {
+			return 0, false
+		}
+		nextIdx++
+		return nextIdx - 1, true
+	}
+
+	workerFn := func() {
+		defer wg.Done()
+		defer func() {
+			if recoverObj := recover(); recoverObj != nil {
+				// Stack is captured here, while worker frames are still on the stack.
+				workerPanic := &WorkerPanic{Value: recoverObj, Stack: debug.Stack()}
+				mu.Lock()
+				defer mu.Unlock()
+				failed = true
+				if panicObj == nil {
+					panicObj = workerPanic
+				}
+			}
+		}()
+		for idx, ok := nextFn(); ok; idx, ok = nextFn() {
+			value, err := fn(in[idx])
+			if err != nil {
+				mu.Lock()
+				failed = true
+				mu.Unlock()
+				errs[idx] = &IndexError{Index: idx, Err: err}
+			}
+			result[idx] = value
+		}
+	}
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go workerFn()
+	}
+	wg.Wait()
+
+	if panicObj != nil {
+		panic(panicObj)
+	}
+	ef.implementCheckAll(nil, errs)
+	return result
+}
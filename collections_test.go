@@ -0,0 +1,152 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Map(t *testing.T) {
+	fn := func(values []string) (result []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return Map(values, strconv.Atoi), nil
+	}
+
+	result, err := fn([]string{"1", "2", "3"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, result)
+
+	_, err = fn([]string{"1", "x", "y"})
+	assert.EqualError(t, err, `index 1: strconv.Atoi: parsing "x": invalid syntax`)
+	var indexErr *IndexError
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Equal(t, 1, indexErr.Index)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+}
+
+func Test_Filter(t *testing.T) {
+	fn := func(values []int) (result []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return Filter(values, func(value int) (bool, error) {
+			if value < 0 {
+				return false, fmt.Errorf("negative value")
+			}
+			return value%2 == 0, nil
+		}), nil
+	}
+
+	result, err := fn([]int{1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, result)
+
+	_, err = fn([]int{1, 2, -3, 4})
+	assert.EqualError(t, err, "index 2: negative value")
+}
+
+func Test_Reduce(t *testing.T) {
+	fn := func(values []int) (result int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return Reduce(values, 0, func(sum int, value int) (int, error) {
+			if value < 0 {
+				return 0, fmt.Errorf("negative value")
+			}
+			return sum + value, nil
+		}), nil
+	}
+
+	result, err := fn([]int{1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, result)
+
+	_, err = fn([]int{1, -2})
+	assert.EqualError(t, err, "index 1: negative value")
+}
+
+func Test_ParallelMap(t *testing.T) {
+	fn := func(values []string) (result []int, err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		return ParallelMap(values, 2, strconv.Atoi), nil
+	}
+
+	result, err := fn([]string{"1", "2", "3", "4", "5"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, result)
+
+	_, err = fn([]string{"1", "x", "3", "y"})
+	assert.EqualError(t, err, "combined error {"+
+		`index 1: strconv.Atoi: parsing "x": invalid syntax; `+
+		`index 3: strconv.Atoi: parsing "y": invalid syntax}`)
+}
+
+func Test_ParallelMap_ReturnFirst(t *testing.T) {
+	fn := func(values []string) (result []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return ParallelMap(values, 0, strconv.Atoi), nil
+	}
+
+	_, err := fn([]string{"1", "x", "3", "y"})
+	assert.EqualError(t, err, `index 1: strconv.Atoi: parsing "x": invalid syntax`)
+}
+
+func Test_ParallelMap_Panic(t *testing.T) {
+	fn := func() (result []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return ParallelMap([]int{1, 2, 3}, 3, func(value int) (int, error) {
+			if value == 2 {
+				panic("worker panic")
+			}
+			return value, nil
+		}), nil
+	}
+
+	var recoverObj interface{}
+	func() {
+		defer func() { recoverObj = recover() }()
+		_, _ = fn()
+	}()
+
+	workerPanic, ok := recoverObj.(*WorkerPanic)
+	if assert.True(t, ok) {
+		assert.Equal(t, "worker panic", workerPanic.Value)
+		assert.Contains(t, string(workerPanic.Stack), "Test_ParallelMap_Panic")
+	}
+}
+
+func Test_ParallelMap_StopOnErr(t *testing.T) {
+	var calls int32
+	fn := func(stopOnErr bool) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		atomic.StoreInt32(&calls, 0)
+		pf := Parallel
+		if stopOnErr {
+			pf = pf.StopOnErr()
+		}
+		ParallelMapWith(pf, []int{1, 2, 3, 4, 5}, 1, func(value int) (int, error) {
+			atomic.AddInt32(&calls, 1)
+			return 0, fmt.Errorf("error %d", value)
+		})
+		return nil
+	}
+
+	assert.EqualError(t, fn(false), "index 0: error 1")
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+
+	assert.EqualError(t, fn(true), "index 0: error 1")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_ParallelMap_Options(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		ParallelMapWith(Parallel.With(WrapperFmtErrorw("wrapped")).StopOnErr(), []int{1}, 1, func(value int) (int, error) {
+			return 0, fmt.Errorf("error %d", value)
+		})
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: index 0: error 1")
+}
@@ -0,0 +1,111 @@
+package errf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NotFoundError is an error produced by comma-ok Check* functions
+// (CheckOk, CheckKey, CheckRecv), when ok value is false.
+type NotFoundError struct {
+	// Key is a map key, which was not found (set only by CheckKey).
+	Key interface{}
+	// Message is an error message.
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+// Format implements fmt.Formatter.
+func (e *NotFoundError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*NotFoundError) errfFormatted() {}
+
+// TypeAssertionError is an error produced by CheckType, when type assertion fails.
+type TypeAssertionError struct {
+	// Value is a value, which failed type assertion.
+	Value interface{}
+	// Type is a type, which was expected.
+	Type reflect.Type
+}
+
+func (e *TypeAssertionError) Error() string {
+	return fmt.Sprintf("type assertion failed: %T is not %v", e.Value, e.Type)
+}
+
+// Format implements fmt.Formatter.
+func (e *TypeAssertionError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*TypeAssertionError) errfFormatted() {}
+
+// CheckOk sends *NotFoundError to IfError() handler for processing, if ok is false.
+// If ok is true, it returns value.
+//
+// Example:
+//  value, ok := os.LookupEnv("HOME")
+//  home := errf.CheckOk(value, ok, "%s is not set", "HOME")
+func CheckOk[T any](value T, ok bool, format string, a ...interface{}) T {
+	var err error
+	if !ok {
+		err = &NotFoundError{Message: fmt.Sprintf(format, a...)}
+	}
+	DefaultErrflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckType sends *TypeAssertionError to IfError() handler for processing,
+// if x doesn't have type T. Otherwise, it returns x.(T).
+//
+// Example:
+//  writer := errf.CheckType[io.Writer](value)
+func CheckType[T any](x interface{}) T {
+	value, ok := x.(T)
+	var err error
+	if !ok {
+		err = &TypeAssertionError{
+			Value: x,
+			Type:  reflect.TypeOf((*T)(nil)).Elem(),
+		}
+	}
+	DefaultErrflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckKey sends *NotFoundError to IfError() handler for processing,
+// if map m doesn't contain key k. Otherwise, it returns m[k].
+//
+// Example:
+//  port := errf.CheckKey(config, "port")
+func CheckKey[K comparable, V any](m map[K]V, k K) V {
+	value, ok := m[k]
+	var err error
+	if !ok {
+		err = &NotFoundError{
+			Key:     k,
+			Message: fmt.Sprintf("key not found: %v", k),
+		}
+	}
+	DefaultErrflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckRecv receives a value from channel ch and sends *NotFoundError
+// to IfError() handler for processing, if ch is closed.
+//
+// Example:
+//  result := errf.CheckRecv(resultsCh)
+func CheckRecv[T any](ch <-chan T) T {
+	value, ok := <-ch
+	var err error
+	if !ok {
+		err = &NotFoundError{Message: "channel is closed"}
+	}
+	DefaultErrflow.ImplementCheck(recover(), err)
+	return value
+}
@@ -0,0 +1,98 @@
+package errf
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CheckOk(t *testing.T) {
+	fn := func(ok bool) (value string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return CheckOk("value", ok, "missing %s", "value"), nil
+	}
+
+	value, err := fn(true)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	_, err = fn(false)
+	assert.EqualError(t, err, "missing value")
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.Nil(t, notFoundErr.Key)
+}
+
+func Test_CheckType(t *testing.T) {
+	fn := func(x interface{}) (value io.Writer, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return CheckType[io.Writer](x), nil
+	}
+
+	writer := &IoWriteReadCloser{}
+	value, err := fn(writer)
+	assert.NoError(t, err)
+	assert.Same(t, writer, value)
+
+	_, err = fn(123)
+	assert.EqualError(t, err, "type assertion failed: int is not io.Writer")
+	var typeErr *TypeAssertionError
+	assert.True(t, errors.As(err, &typeErr))
+	assert.Equal(t, 123, typeErr.Value)
+	assert.Equal(t, reflect.TypeOf((*io.Writer)(nil)).Elem(), typeErr.Type)
+}
+
+func Test_CheckKey(t *testing.T) {
+	m := map[string]int{"one": 1}
+	fn := func(key string) (value int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return CheckKey(m, key), nil
+	}
+
+	value, err := fn("one")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = fn("two")
+	assert.EqualError(t, err, "key not found: two")
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.Equal(t, "two", notFoundErr.Key)
+}
+
+func Test_CheckRecv(t *testing.T) {
+	ch := make(chan int, 1)
+	fn := func() (value int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return CheckRecv(ch), nil
+	}
+
+	ch <- 1
+	close(ch)
+
+	value, err := fn()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	_, err = fn()
+	assert.EqualError(t, err, "channel is closed")
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func Test_CheckKey_Wrapped(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().Apply(WrapperFmtErrorw("wrapped")).ThenAssignTo(&err)
+		CheckKey(map[int]int{}, 1)
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "wrapped: key not found: 1")
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.Equal(t, 1, notFoundErr.Key)
+}
@@ -0,0 +1,83 @@
+package errf
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// Compress contains collection of Check* functions for compress/* types.
+var Compress = CompressErrflow{}
+
+// CompressErrflow implements Check* functions for compress/* packages types.
+//
+// Clients should not instantiate CompressErrflow, use 'errf.Compress' instead.
+type CompressErrflow struct {
+	errflow *Errflow
+}
+
+// With implements Errflow.With(...) for compress/* types.
+func (ef CompressErrflow) With(options ...ErrflowOption) CompressErrflow {
+	return CompressErrflow{errflow: ef.errflow.With(options...)}
+}
+
+// CheckGzipReader calls errf.Check and returns a typed value from a function call.
+func (ef CompressErrflow) CheckGzipReader(value *gzip.Reader, err error) *gzip.Reader {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckGzipWriter calls errf.Check and returns a typed value from a function call.
+func (ef CompressErrflow) CheckGzipWriter(value *gzip.Writer, err error) *gzip.Writer {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckZlibReader calls errf.Check and returns a typed value from a function call.
+func (ef CompressErrflow) CheckZlibReader(value io.ReadCloser, err error) io.ReadCloser {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckZlibWriter calls errf.Check and returns a typed value from a function call.
+func (ef CompressErrflow) CheckZlibWriter(value *zlib.Writer, err error) *zlib.Writer {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckFlateWriter calls errf.Check and returns a typed value from a function call.
+func (ef CompressErrflow) CheckFlateWriter(value *flate.Writer, err error) *flate.Writer {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value
+}
+
+// CheckGzipReaderErr calls errf.Check and returns a typed value and error from a function call.
+func (ef CompressErrflow) CheckGzipReaderErr(value *gzip.Reader, err error) (*gzip.Reader, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckGzipWriterErr calls errf.Check and returns a typed value and error from a function call.
+func (ef CompressErrflow) CheckGzipWriterErr(value *gzip.Writer, err error) (*gzip.Writer, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckZlibReaderErr calls errf.Check and returns a typed value and error from a function call.
+func (ef CompressErrflow) CheckZlibReaderErr(value io.ReadCloser, err error) (io.ReadCloser, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckZlibWriterErr calls errf.Check and returns a typed value and error from a function call.
+func (ef CompressErrflow) CheckZlibWriterErr(value *zlib.Writer, err error) (*zlib.Writer, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
+
+// CheckFlateWriterErr calls errf.Check and returns a typed value and error from a function call.
+func (ef CompressErrflow) CheckFlateWriterErr(value *flate.Writer, err error) (*flate.Writer, error) {
+	ef.errflow.ImplementCheck(recover(), err)
+	return value, err
+}
@@ -0,0 +1,96 @@
+package errf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Compress_With(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Compress.With(WrapperFmtErrorw("wrapped")).CheckGzipReader(gzip.NewReader(&bytes.Buffer{}))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: EOF")
+}
+
+func Test_Compress_Gzip(t *testing.T) {
+	fn := func(level int) (data []byte, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		var buf bytes.Buffer
+		writer := Compress.CheckGzipWriter(gzip.NewWriterLevel(&buf, level))
+		CheckDiscard(writer.Write([]byte("hello")))
+		CheckErr(writer.Close())
+
+		reader := Compress.CheckGzipReader(gzip.NewReader(&buf))
+		defer CheckDeferErr(reader.Close)
+		return CheckAny(io.ReadAll(reader)).([]byte), nil
+	}
+
+	data, err := fn(gzip.BestCompression)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = fn(100)
+	assert.EqualError(t, err, "gzip: invalid compression level: 100")
+}
+
+func Test_Compress_Zlib(t *testing.T) {
+	fn := func(level int) (data []byte, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		var buf bytes.Buffer
+		writer := Compress.CheckZlibWriter(zlib.NewWriterLevel(&buf, level))
+		CheckDiscard(writer.Write([]byte("hello")))
+		CheckErr(writer.Close())
+
+		reader := Compress.CheckZlibReader(zlib.NewReader(&buf))
+		defer CheckDeferErr(reader.Close)
+		return CheckAny(io.ReadAll(reader)).([]byte), nil
+	}
+
+	data, err := fn(zlib.BestSpeed)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = fn(100)
+	assert.EqualError(t, err, "zlib: invalid compression level: 100")
+}
+
+func Test_Compress_CheckFlateWriter(t *testing.T) {
+	fn := func(level int) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		writer := Compress.CheckFlateWriter(flate.NewWriter(io.Discard, level))
+		return CheckErr(writer.Close()).IfOkReturnNil
+	}
+
+	assert.NoError(t, fn(flate.DefaultCompression))
+	assert.EqualError(t, fn(100), "flate: invalid compression level 100: want value in range [-2, 9]")
+}
+
+func Test_Compress_CheckErrFunctions(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		defer Compress.CheckGzipReaderErr(nil, fmt.Errorf("error 1"))
+		defer Compress.CheckGzipWriterErr(nil, fmt.Errorf("error 2"))
+		defer Compress.CheckZlibReaderErr(nil, fmt.Errorf("error 3"))
+		defer Compress.CheckZlibWriterErr(nil, fmt.Errorf("error 4"))
+		defer Compress.CheckFlateWriterErr(nil, fmt.Errorf("error 5"))
+
+		writer, checkErr := Compress.CheckGzipWriterErr(gzip.NewWriterLevel(io.Discard, gzip.BestSpeed))
+		assert.NotNil(t, writer)
+		assert.NoError(t, checkErr)
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "combined error {error 5; error 4; error 3; error 2; error 1}")
+}
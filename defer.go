@@ -35,26 +35,35 @@ func (ef *Errflow) IfErrorAssignTo(outErr *error, closeFn func() error) {
 			panic("error wrapper returned nil error")
 		}
 		if *outErr == nil {
-			*outErr = err
-			if ef.logStrategy == logStrategyAlways {
+			if ef.logStrategy == logStrategyAlways && ef.shouldLog(err) {
 				globalLogFn(&LogMessage{
 					Format: "%s",
 					A:      []interface{}{err.Error()},
-					Stack:  getStringErrorStackTraceFn(),
+					Stack:  getStringErrorStackTraceFnFor(err),
 					Tags:   []string{"errorflow", "error"},
 				})
+				err = markLogged(err)
 			}
+			*outErr = err
 		} else {
-			_, supp2, resultErr := getReturnStrategyImpl(ef.returnStrategy)(*outErr, err)
-			*outErr = resultErr
-			if (supp2 && ef.logStrategy == logStrategyIfSuppressed) || ef.logStrategy == logStrategyAlways {
+			returnStrategyImpl := getReturnStrategyImpl(ef.returnStrategy)
+			_, supp2, _ := returnStrategyImpl(*outErr, err)
+			if ((supp2 && ef.logStrategy == logStrategyIfSuppressed) || ef.logStrategy == logStrategyAlways) &&
+				ef.shouldLog(err) {
 				globalLogFn(&LogMessage{
 					Format: "%s",
 					A:      []interface{}{err.Error()},
-					Stack:  getStringErrorStackTraceFn(),
+					Stack:  getStringErrorStackTraceFnFor(err),
 					Tags:   []string{"errorflow", "suppressed-error"},
 				})
+				err = markLogged(err)
 			}
+			// Result is computed after logging, so it includes err marked as logged.
+			_, _, resultErr := returnStrategyImpl(*outErr, err)
+			if supp2 && ef.attachSuppressed {
+				resultErr = attachSuppressed(resultErr, []error{err})
+			}
+			*outErr = resultErr
 		}
 	}
 }
@@ -71,6 +71,43 @@ func Test_IfErrorAssignTo_LogAll(t *testing.T) {
 	assert.Equal(t, []string{"error1", "error2"}, logs)
 }
 
+func Test_IfErrorAssignTo_LogOnce(t *testing.T) {
+	logCalls := 0
+	defer SetLogFn(func(logMessage *LogMessage) {
+		logCalls++
+	}).ThenRestore()
+
+	inner := func() (err error) {
+		defer With(LogStrategyAlways, ReturnStrategyCombined).IfErrorAssignTo(&err, errorFn("error2"))
+		defer With(LogStrategyAlways).IfErrorAssignTo(&err, errorFn("error1"))
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		return CheckErr(inner()).IfOkReturnNil
+	}
+
+	err := outer()
+	assert.EqualError(t, err, "combined error {error1; error2}")
+	assert.True(t, IsLogged(err))
+	assert.Equal(t, 2, logCalls)
+}
+
+func Test_IfErrorAssignTo_AttachSuppressed(t *testing.T) {
+	fn := func() (err error) {
+		defer With(AttachSuppressed).IfErrorAssignTo(&err, errorFn("error3"))
+		defer With(AttachSuppressed).IfErrorAssignTo(&err, errorFn("error2"))
+		defer IfErrorAssignTo(&err, errorFn("error1"))
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "error1")
+	assert.Equal(t, 2, len(Suppressed(err)))
+	assert.EqualError(t, Suppressed(err)[0], "error2")
+	assert.EqualError(t, Suppressed(err)[1], "error3")
+}
+
 func Test_IfErrorAssignTo_LogIfSuppressed(t *testing.T) {
 	var logs []string
 	defer SetLogFn(func(logMessage *LogMessage) {
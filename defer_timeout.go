@@ -0,0 +1,134 @@
+package errf
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock defines time API, which is used by errflow (e.g. for close timeouts).
+type Clock interface {
+	// After has the same semantics as time.After.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+var globalClock Clock = realClock{}
+
+type clockRestorer struct {
+	oldClock Clock
+}
+
+func (cr *clockRestorer) ThenRestore() {
+	globalClock = cr.oldClock
+}
+
+// SetClock replaces clock for errflow, which is useful for testing.
+// It returns errf.DeferRestorer instance,
+// which can be used to restore previous clock, if needed.
+// Default clock uses time package.
+func SetClock(clock Clock) DeferRestorer {
+	oldClock := globalClock
+	globalClock = clock
+	return &clockRestorer{
+		oldClock: oldClock,
+	}
+}
+
+// CloseTimeoutError is an error produced by CheckDeferErrTimeout and LogDeferTimeout,
+// when close function doesn't finish in time.
+type CloseTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *CloseTimeoutError) Error() string {
+	return fmt.Sprintf("close timed out after %v", e.Timeout)
+}
+
+// Format implements fmt.Formatter.
+func (e *CloseTimeoutError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*CloseTimeoutError) errfFormatted() {}
+
+// CloseTimeoutAbort configures Errflow instance to call abortFn,
+// when close function in CheckDeferErrTimeout or LogDeferTimeout doesn't finish in time.
+//
+// Example:
+//  defer errf.With(errf.CloseTimeoutAbort(cancelUpload)).CheckDeferErrTimeout(upload.Close, time.Minute)
+func CloseTimeoutAbort(abortFn func()) ErrflowOption {
+	return func(ef *Errflow) *Errflow {
+		newEf := ef.copy()
+		newEf.closeTimeoutAbortFn = abortFn
+		return newEf
+	}
+}
+
+type closeResult struct {
+	err      error
+	panicObj interface{}
+}
+
+func (ef *Errflow) closeWithTimeout(closeFn func() error, timeout time.Duration) error {
+	ef.applyDeferredOptions()
+
+	done := make(chan closeResult, 1)
+	go func() {
+		var result closeResult
+		defer func() {
+			result.panicObj = recover()
+			done <- result
+		}()
+		result.err = closeFn()
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicObj != nil {
+			panic(result.panicObj)
+		}
+		return result.err
+	case <-globalClock.After(timeout):
+		if ef.closeTimeoutAbortFn != nil {
+			ef.closeTimeoutAbortFn()
+		}
+		return &CloseTimeoutError{Timeout: timeout}
+	}
+}
+
+// CheckDeferErrTimeout calls closeFn and checks for return error, same as CheckDeferErr.
+// If closeFn doesn't finish in timeout, *CloseTimeoutError is checked instead
+// and closeFn is left running in a separate goroutine.
+//
+// See also: CloseTimeoutAbort.
+//
+// Useful in defer statements:
+//  writer := ...
+//  defer errf.CheckDeferErrTimeout(writer.Close, 10*time.Second)
+func (ef *Errflow) CheckDeferErrTimeout(closeFn func() error, timeout time.Duration) CheckResult {
+	return ef.ImplementCheck(recover(), ef.closeWithTimeout(closeFn, timeout))
+}
+
+// CheckDeferErrTimeout is an alias for DefaultErrflow.CheckDeferErrTimeout(...).
+func CheckDeferErrTimeout(closeFn func() error, timeout time.Duration) CheckResult {
+	return DefaultErrflow.ImplementCheck(recover(), DefaultErrflow.closeWithTimeout(closeFn, timeout))
+}
+
+// LogDeferTimeout calls closeFn, then calls Log(...) on result of a call, same as LogDefer.
+// If closeFn doesn't finish in timeout, *CloseTimeoutError is logged instead
+// and closeFn is left running in a separate goroutine.
+//
+// See also: CloseTimeoutAbort.
+func (ef *Errflow) LogDeferTimeout(closeFn func() error, timeout time.Duration) {
+	ef.Log(ef.closeWithTimeout(closeFn, timeout))
+}
+
+// LogDeferTimeout is an alias for DefaultErrflow.LogDeferTimeout(...).
+func LogDeferTimeout(closeFn func() error, timeout time.Duration) {
+	DefaultErrflow.LogDeferTimeout(closeFn, timeout)
+}
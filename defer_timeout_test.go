@@ -0,0 +1,114 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	ch      chan time.Time
+	timeout time.Duration
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.timeout = d
+	return c.ch
+}
+
+func Test_CheckDeferErrTimeout(t *testing.T) {
+	clock := &testClock{ch: make(chan time.Time)}
+	defer SetClock(clock).ThenRestore()
+
+	fn := func(closeErr error) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer CheckDeferErrTimeout(func() error { return closeErr }, time.Second)
+		return nil
+	}
+
+	assert.NoError(t, fn(nil))
+	assert.EqualError(t, fn(fmt.Errorf("close error")), "close error")
+	assert.Equal(t, time.Second, clock.timeout)
+}
+
+func Test_CheckDeferErrTimeout_Timeout(t *testing.T) {
+	clock := &testClock{ch: make(chan time.Time, 1)}
+	defer SetClock(clock).ThenRestore()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+	aborted := false
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer With(CloseTimeoutAbort(func() { aborted = true })).CheckDeferErrTimeout(func() error {
+			<-unblock
+			return nil
+		}, time.Minute)
+		clock.ch <- time.Now()
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "close timed out after 1m0s")
+	var timeoutErr *CloseTimeoutError
+	assert.True(t, errors.As(err, &timeoutErr))
+	assert.Equal(t, time.Minute, timeoutErr.Timeout)
+	assert.True(t, aborted)
+}
+
+func Test_CheckDeferErrTimeout_Panic(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer CheckDeferErrTimeout(func() error { panic("close panic") }, time.Minute)
+		return nil
+	}
+
+	assert.PanicsWithValue(t, "close panic", func() {
+		_ = fn()
+	})
+}
+
+func Test_CheckDeferErrTimeout_RealClock(t *testing.T) {
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer With().CheckDeferErrTimeout(func() error {
+			<-unblock
+			return nil
+		}, time.Millisecond)
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "close timed out after 1ms")
+}
+
+func Test_LogDeferTimeout(t *testing.T) {
+	clock := &testClock{ch: make(chan time.Time, 1)}
+	defer SetClock(clock).ThenRestore()
+
+	var logs []string
+	defer SetLogFn(func(logMessage *LogMessage) {
+		logs = append(logs, fmt.Sprintf(logMessage.Format, logMessage.A...))
+	}).ThenRestore()
+
+	unblock := make(chan struct{})
+	defer close(unblock)
+
+	fn := func(closeFn func() error) {
+		defer LogDeferTimeout(closeFn, time.Second)
+	}
+
+	fn(errorFn("close error"))
+	clock.ch <- time.Now()
+	fn(func() error {
+		<-unblock
+		return nil
+	})
+	assert.Equal(t, []string{"close error", "close timed out after 1s"}, logs)
+}
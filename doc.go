@@ -47,7 +47,8 @@
 //  		/* same as */
 //  	typedValue := errf.CheckAny(someFunction3()).(type)
 //  	// NOTE: using CheckAny is not recommended,
-//  	// clients should prefer using either standard typed function
+//  	// clients should prefer using either standard typed function,
+//  	// generic errf.Check(someFunction3())
 //  	// or create custom ones (see 'Extending errorflow for custom types' below).
 //
 //  	// Checking err using return value (typed).
@@ -71,6 +72,18 @@
 //
 // Never use Check* functions in functions without IfError() handler set up
 // (including nested anonymous functions).
+// Otherwise, failed Check* call crashes the program with "errflow: unhandled error" panic,
+// which names the error and the Check* call site.
+// errf.Guard() can be deferred at goroutine entry points to log such errors instead.
+//
+// The only exception are callbacks wrapped using callback adapters (e.g. errf.Func1,
+// errf.WalkDirFunc), which execute callback body in an implicit IfError() scope
+// and return errors from the callback:
+//
+//  err := filepath.WalkDir(root, errf.WalkDirFunc(func(path string, d fs.DirEntry) {
+//  	info := errf.CheckAny(d.Info()).(fs.FileInfo)
+//  	// ...
+//  }))
 //
 // Usage correctness validation
 //
@@ -94,6 +107,18 @@
 //  	// Validator will be disabled until the end of this function.
 //  }
 //
+// Building with "errf_novalidator" build tag compiles validator out completely:
+//
+//  go build -tags errf_novalidator ./...
+//
+// Performance
+//
+// Check* functions don't allocate memory when there is no error. Stack traces for log messages
+// are captured as program counters and only formatted when LogFn reads LogMessage.Stack.
+// See benchmark_test.go for comparison with plain 'if err != nil' error handling:
+//
+//  go test -bench . -benchmem
+//
 // Return Strategy
 //
 // Return strategy controls what error to return from a function in case if multiple errors
@@ -130,6 +155,13 @@
 //                     errf.GetCombinedErrors(err) ->
 //                       {fmt.Errorf("error 1"), fmt.Errorf("error 2"), fmt.Errorf("error 3")}
 //
+// Errors, which are not present in resulting error (e.g. "error 2" and "error 3" for ReturnFirst),
+// can be attached to it using AttachSuppressed() and retrieved using errf.Suppressed(err).
+// Callers can then log them (e.g. using "%+v" format) instead of logging them at every level.
+//
+// errf.Format(err) (also used for "%+v" by errflow error types) renders resulting error
+// as an indented tree with all wrapped, combined and suppressed errors.
+//
 // Log Strategy
 //
 // Log strategy controls IfError() handler logging behavior.
@@ -152,6 +184,28 @@
 //  * LogIfSuppressed -> "error 2" is logged
 //  * LogAlways -> both "error 1" and "error 2" are logged
 //
+// Each error is logged only once: errflow marks errors it logs (see errf.IsLogged)
+// and skips marked errors, even if multiple functions up the call stack use LogAlways().
+// Use LogForce() to log errors anyway.
+//
+// Options precedence
+//
+// Options are applied in order: Check*-level options (e.g. errf.With(...).CheckErr(err)) first,
+// then IfError()-level options; inside each level, from left to right.
+//
+// For log strategy, return strategy and label, first applied value takes precedence
+// and later values are ignored:
+//  * errf.With(errf.LogStrategyNever).With(errf.LogStrategyAlways) never logs;
+//  * IfError().LogAlways() doesn't log errors from errf.With(errf.LogStrategyNever).CheckErr(...).
+// Wrappers are always applied: Check*-level wrappers first, then IfError()-level wrappers.
+//
+// Use errf.Override to replace already applied values:
+//
+//  defer errf.IfError().Apply(errf.Override(errf.LogStrategyAlways)).ThenAssignTo(&err)
+//
+// SetStrictValidator() makes validator panic when options are ignored because of conflicts,
+// which helps to find contradictory options in tests.
+//
 // Wrappers
 //
 // Wrappers are functions which wrap error objects into other error objects.
@@ -191,6 +245,12 @@
 //  	// business logic ...
 //  }
 //
+// Built-in errf.WrapperAddStack wrapper does exactly that. Its errors expose Cause(), StackTrace()
+// and "%+v" formatting, same as github.com/pkg/errors errors.
+//
+// When errors already carry a stack trace (detected via StackTrace(), Callers(), Cause() methods
+// or "%+v" formatting), errflow reuses it for log messages instead of capturing a new one.
+//
 // Handlers
 //
 // Handlers are used to handle errors and panics that are bubbling up the defers ladder.
@@ -231,6 +291,33 @@
 //  * It is allowed to use Check* funcions inside Handlers even without IfError() set up
 //    inside a handler. In such cases, defer Handle()... enclosing function IfError()
 //    will be used to catch errors.
+//  * Handlers see errors with Check*-level options applied only. To make handlers see
+//    the same error as the one returned from a function (e.g. for errors.Is checks
+//    against sentinels added by IfError()-level wrappers), use IfError().ApplyToHandlers().
+//
+// Check* functions can be tagged using errf.Label option, so handlers can tell which check failed:
+//
+//  writer := errf.Io.With(errf.Label("open-dst")).CheckWriteCloser(os.Create(dst))
+//  defer errf.Handle().OnLabel("open-dst", func(err error) { /* ... */ })
+//
+// errf.LabelOf(err) returns a label in Then*() callbacks. Labels are also added to log tags.
+//
+// Owned resources
+//
+// Instead of pairing each resource with 'defer errf.CheckDeferErr(x.Close)', resources
+// can be owned by IfError() handler scope, which closes them in LIFO order when scope ends:
+//
+//  func copyFile(dst, src string) (err error) {
+//  	defer errf.IfError().Owning().ThenAssignTo(&err)
+//
+//  	reader := errf.Own(os.Open(src))
+//  	writer := errf.Own(os.Create(dst))
+//
+//  	return errf.CheckDiscard(io.Copy(writer, reader)).IfOkReturnNil
+//  }
+//
+// Close errors are handled same as errors from Check* functions.
+// Resources registered using errf.OwnOnErr are closed only if scope fails.
 //
 // Custom log function
 //
@@ -246,6 +333,33 @@
 //  	/* app main function */
 //  }
 //
+// Tracing
+//
+// Tracing records an event log for every IfError() handler scope: Check* calls with
+// call sites and results, applied wrappers, return strategy decisions (including
+// which errors were suppressed), Handle() callbacks and the resulting error.
+//
+// Set ERRF_TRACE=1 environment variable to log traces using errflow log function,
+// or use SetTracer to receive traces programmatically (e.g. in tests):
+//
+//  recorder := &errf.TraceRecorder{}
+//  defer errf.SetTracer(recorder.Record).ThenRestore()
+//
+//  err := functionUnderTest()
+//  t.Log(recorder.Traces())
+//
+// Example trace:
+//  errflow trace: example.copyFile
+//    enter copy.go:10: IfError()
+//    check copy.go:12: OsErrflow.CheckFile: ok
+//    check copy.go:15: CheckDiscard: error "disk full"
+//    check copy.go:15: CheckDeferErr: error "close failed"
+//    combine: ReturnStrategyFirst("disk full", "close failed") = "disk full", suppressed: false, true
+//    leave: error "disk full"
+//
+// Tracing is slow (it captures stack frames for every Check* call) and should only be used
+// for debugging.
+//
 // Helper functions
 //
 // Errflow also implements few helper functions which can be used
@@ -14,6 +14,10 @@ type Errflow struct {
 	wrapper func(err error) error
 	logStrategy
 	returnStrategy
+	label               string
+	attachSuppressed    bool
+	logForce            bool
+	closeTimeoutAbortFn func()
 
 	deferredOptions []ErrflowOption
 	appliedOptions  []ErrflowOption
@@ -35,6 +39,11 @@ func (ef *Errflow) copy() *Errflow {
 		logStrategy:    ef.logStrategy,
 		returnStrategy: ef.returnStrategy,
 
+		label:               ef.label,
+		attachSuppressed:    ef.attachSuppressed,
+		logForce:            ef.logForce,
+		closeTimeoutAbortFn: ef.closeTimeoutAbortFn,
+
 		deferredOptions: ef.deferredOptions,
 		appliedOptions:  ef.appliedOptions,
 	}
@@ -94,6 +103,8 @@ func With(options ...ErrflowOption) *Errflow {
 type errflowThrowItem struct {
 	ef  *Errflow
 	err error
+	// pcs is a stack of Check* call, used for diagnostics of escaped errors.
+	pcs []uintptr
 }
 
 type errflowThrow struct {
@@ -143,11 +154,34 @@ type CheckResult struct {
 //  	// ...
 //  }
 func (ef *Errflow) ImplementCheck(recoverObj interface{}, err error) CheckResult {
+	if recoverObj == nil && err == nil {
+		// Fast path: no allocations on success.
+		if validatorEnabled {
+			globalErrflowValidator.validate()
+		}
+		if globalTracer != nil {
+			traceCheck(nil)
+		}
+		return CheckResult{}
+	}
+	return ef.implementCheckAll(recoverObj, []error{err})
+}
+
+// implementCheckAll is same as ImplementCheck, but it sends all non-nil errors
+// to IfError() handler at once, which combines them using return strategy.
+func (ef *Errflow) implementCheckAll(recoverObj interface{}, errs []error) CheckResult {
 	errflow := ef
 	if errflow == nil {
 		errflow = DefaultErrflow
 	}
-	globalErrflowValidator.validate()
+	if validatorEnabled {
+		globalErrflowValidator.validate()
+	}
+	if globalTracer != nil {
+		for _, err := range errs {
+			traceCheck(err)
+		}
+	}
 
 	var errflowThrowObj errflowThrow
 	if recoverObj != nil {
@@ -158,11 +192,18 @@ func (ef *Errflow) ImplementCheck(recoverObj interface{}, err error) CheckResult
 			panic(recoverObj)
 		}
 	}
-	if err != nil {
-		errflowThrowObj.items = append(errflowThrowObj.items, errflowThrowItem{
-			ef:  errflow,
-			err: err,
-		})
+	var pcs []uintptr
+	for _, err := range errs {
+		if err != nil {
+			if pcs == nil {
+				pcs = callers(1)
+			}
+			errflowThrowObj.items = append(errflowThrowObj.items, errflowThrowItem{
+				ef:  errflow,
+				err: err,
+				pcs: pcs,
+			})
+		}
 	}
 	if len(errflowThrowObj.items) > 0 {
 		panic(errflowThrowObj)
@@ -216,7 +257,7 @@ func CheckDeferErr(closeFn func() error) CheckResult {
 //  }
 //
 // Tip: prefer using typed functions, defined in either this library, or
-// custom ones, implemented using errf.ImplementCheck(...).
+// custom ones, implemented using errf.ImplementCheck(...), or generic errf.Check(...).
 //
 // Example above can usually rewritten as:
 //  function ProcessFile() (err error) {
@@ -238,6 +279,21 @@ func CheckAny(value interface{}, err error) interface{} {
 	return value
 }
 
+// Check sends error to IfError() handler for processing, if there is an error.
+// If there is no error, it returns a value from a function call.
+//
+// Check is a type safe replacement for CheckAny, for types without
+// typed Check* functions.
+//
+// Example:
+//  cfg := errf.Check(loadConfig(filename))
+//  	/* same as */
+//  cfg := errf.CheckAny(loadConfig(filename)).(*Config)
+func Check[T any](value T, err error) T {
+	DefaultErrflow.ImplementCheck(recover(), err)
+	return value
+}
+
 // CheckDiscard sends error to IfError() handler for processing, if there is an error.
 // Non-error value returned from a function is discarded.
 //
@@ -291,9 +347,15 @@ func CheckAssert(condition bool, format string, a ...interface{}) CheckResult {
 // Log logs error, if not nil.
 // Always logs, even if log strategy is LogStrategyNever.
 // Doesn't affect control flow.
+//
+// Errors, which were already logged by errflow, are not logged again,
+// unless LogForce option is used (see IsLogged).
 func (ef *Errflow) Log(err error) {
 	if err != nil {
 		ef.applyDeferredOptions()
+		if !ef.shouldLog(err) {
+			return
+		}
 		if ef.wrapper != nil {
 			err = ef.wrapper(err)
 		}
@@ -303,7 +365,7 @@ func (ef *Errflow) Log(err error) {
 		globalLogFn(&LogMessage{
 			Format: "%s",
 			A:      []interface{}{err.Error()},
-			Stack:  getStringErrorStackTraceFn(),
+			Stack:  getStringErrorStackTraceFnFor(err),
 			Tags:   []string{"errorflow", "error"},
 		})
 	}
@@ -15,3 +15,40 @@ func Opts(options ...ErrflowOption) ErrflowOption {
 func OptsFrom(ef *Errflow) ErrflowOption {
 	return Opts(ef.Opts()...)
 }
+
+// Override creates ErrflowOption, which applies option even if it configures
+// a value already set by previously applied options.
+//
+// By default, first applied strategy (or label) takes precedence: Check*-level options
+// are applied before IfError()-level options, and in errf.With(...) chains, options are
+// applied from left to right. Override replaces log strategy, return strategy and label
+// if option sets them. Other configs (e.g. wrappers) are applied as usual.
+//
+// Example:
+//  func example() (err error) {
+//  	// Logs errors even if Check* function is configured with errf.LogStrategyNever.
+//  	defer errf.IfError().Apply(errf.Override(errf.LogStrategyAlways)).ThenAssignTo(&err)
+//
+//  	errf.With(errf.LogStrategyNever).CheckErr(cleanup())
+//  	// ...
+//  }
+func Override(option ErrflowOption) ErrflowOption {
+	return func(ef *Errflow) *Errflow {
+		base := ef.copy()
+		base.logStrategy = logStrategyDefault
+		base.returnStrategy = returnStrategyDefault
+		base.label = ""
+
+		newEf := option(base).copy()
+		if newEf.logStrategy == logStrategyDefault {
+			newEf.logStrategy = ef.logStrategy
+		}
+		if newEf.returnStrategy == returnStrategyDefault {
+			newEf.returnStrategy = ef.returnStrategy
+		}
+		if newEf.label == "" {
+			newEf.label = ef.label
+		}
+		return newEf
+	}
+}
@@ -0,0 +1,54 @@
+package errf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Opts_FirstAppliedTakesPrecedence(t *testing.T) {
+	ef := With(LogStrategyNever).With(LogStrategyAlways, ReturnStrategyLast, ReturnStrategyWrapped)
+	ef.applyDeferredOptions()
+
+	assert.Equal(t, logStrategyNever, ef.logStrategy)
+	assert.Equal(t, returnStrategyLast, ef.returnStrategy)
+}
+
+func Test_Override(t *testing.T) {
+	ef := With(LogStrategyNever, ReturnStrategyLast, Label("first")).
+		With(Override(Opts(LogStrategyAlways, Label("second"))))
+	ef.applyDeferredOptions()
+
+	assert.Equal(t, logStrategyAlways, ef.logStrategy)
+	assert.Equal(t, returnStrategyLast, ef.returnStrategy)
+	assert.Equal(t, "second", ef.label)
+}
+
+func Test_Override_KeepsWrappers(t *testing.T) {
+	ef := With(WrapperFmtErrorw("inner"), Override(WrapperFmtErrorw("outer")))
+	ef.applyDeferredOptions()
+
+	assert.EqualError(t, ef.wrapper(fmt.Errorf("error")), "outer: inner: error")
+}
+
+func Test_Override_IfErrorLevel(t *testing.T) {
+	var logs []string
+	defer SetLogFn(func(logMessage *LogMessage) {
+		logs = append(logs, fmt.Sprintf(logMessage.Format, logMessage.A...))
+	}).ThenRestore()
+
+	fn := func(options ...ErrflowOption) (err error) {
+		defer IfError().Apply(options...).ThenAssignTo(&err)
+
+		defer With(ReturnStrategyFirst, LogStrategyNever).CheckErr(fmt.Errorf("error2"))
+		return CheckErr(fmt.Errorf("error1")).IfOkReturnNil
+	}
+
+	assert.EqualError(t, fn(LogStrategyAlways, ReturnStrategyLast), "error1")
+	assert.Equal(t, []string{"error1"}, logs)
+	logs = nil
+
+	assert.EqualError(t, fn(Override(LogStrategyAlways), Override(ReturnStrategyLast)), "error2")
+	assert.Equal(t, []string{"error1", "error2"}, logs)
+}
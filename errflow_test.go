@@ -2,6 +2,7 @@ package errf
 
 import (
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -53,6 +54,21 @@ func TestErrflow_CheckAny(t *testing.T) {
 	assert.EqualError(t, fn(), "error1 (also: error2)")
 }
 
+func TestErrflow_Check(t *testing.T) {
+	fn := func() (value int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		value = Check(42, nil)
+		assert.Equal(t, 42, value)
+		var reader io.Reader = Check[io.Reader](nil, nil)
+		assert.Nil(t, reader)
+		return Check(0, fmt.Errorf("error1")), nil
+	}
+
+	value, err := fn()
+	assert.Equal(t, 42, value)
+	assert.EqualError(t, err, "error1")
+}
+
 func TestErrflow_CheckDiscard(t *testing.T) {
 	fn := func() (err error) {
 		defer IfError().ReturnWrapped().ThenAssignTo(&err)
@@ -0,0 +1,281 @@
+package errf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// errorJSON is a JSON representation of an error tree.
+type errorJSON struct {
+	Message    string                 `json:"message"`
+	Type       string                 `json:"type"`
+	Sentinel   string                 `json:"sentinel,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	Stack      []string               `json:"stack,omitempty"`
+	CallSite   string                 `json:"call_site,omitempty"`
+	Wrapped    *errorJSON             `json:"wrapped,omitempty"`
+	Combined   []*errorJSON           `json:"combined,omitempty"`
+	Suppressed []*errorJSON           `json:"suppressed,omitempty"`
+}
+
+var sentinels = struct {
+	sync.RWMutex
+	byName map[string]error
+}{
+	byName: map[string]error{
+		"io.EOF":                   io.EOF,
+		"io.ErrUnexpectedEOF":      io.ErrUnexpectedEOF,
+		"context.Canceled":         context.Canceled,
+		"context.DeadlineExceeded": context.DeadlineExceeded,
+		"fs.ErrNotExist":           fs.ErrNotExist,
+		"fs.ErrExist":              fs.ErrExist,
+		"fs.ErrPermission":         fs.ErrPermission,
+	},
+}
+
+// RegisterSentinel registers a sentinel error (e.g. io.EOF) under a name.
+//
+// MarshalError stores only a name for sentinel errors,
+// and UnmarshalError restores the original instance, so errors.Is works
+// after deserialization.
+//
+// Common sentinels from io, context and io/fs packages are registered by default.
+//
+// Example:
+//  var ErrNotFound = errors.New("not found")
+//
+//  func init() {
+//  	errf.RegisterSentinel("myapp.ErrNotFound", ErrNotFound)
+//  }
+func RegisterSentinel(name string, err error) {
+	sentinels.Lock()
+	defer sentinels.Unlock()
+	sentinels.byName[name] = err
+}
+
+func sentinelName(err error) (string, bool) {
+	if !reflect.TypeOf(err).Comparable() {
+		return "", false
+	}
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+	for name, sentinel := range sentinels.byName {
+		if reflect.TypeOf(sentinel) == reflect.TypeOf(err) && sentinel == err {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func lookupSentinel(name string) (error, bool) {
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+	err, ok := sentinels.byName[name]
+	return err, ok
+}
+
+// MarshalError serializes err into JSON.
+//
+// Resulting JSON is a tree, which contains messages, types, wrapped errors,
+// combined and suppressed errors, fields of errflow errors (e.g. IndexError.Index),
+// stack traces and call sites.
+//
+// See also: UnmarshalError, RegisterSentinel.
+func MarshalError(err error) ([]byte, error) {
+	return json.Marshal(marshalErrorTree(err))
+}
+
+func marshalErrorTree(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+	if logged, ok := err.(*loggedError); ok {
+		return marshalErrorTree(logged.err)
+	}
+
+	node := &errorJSON{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	if name, ok := sentinelName(err); ok {
+		node.Sentinel = name
+		return node
+	}
+
+	switch e := err.(type) {
+	case *DecodedError:
+		node.Type = e.Type
+		node.Fields = e.Fields
+		node.Stack = e.Stack
+		node.CallSite = e.CallSite
+	case CombinedError:
+		for _, child := range e.errs {
+			node.Combined = append(node.Combined, marshalErrorTree(child))
+		}
+		return node
+	case *SuppressedError:
+		for _, suppressed := range e.Suppressed {
+			node.Suppressed = append(node.Suppressed, marshalErrorTree(suppressed))
+		}
+	case *LabeledError:
+		node.Fields = map[string]interface{}{"label": e.Label}
+	case *IndexError:
+		node.Fields = map[string]interface{}{"index": e.Index}
+	case *StickyError:
+		node.Fields = map[string]interface{}{"offset": e.Offset}
+	case *NotFoundError:
+		node.Fields = map[string]interface{}{"key": fmt.Sprint(e.Key)}
+	case *CloseTimeoutError:
+		node.Fields = map[string]interface{}{"timeout": e.Timeout.String()}
+	case PanicErr:
+		node.Fields = map[string]interface{}{"panic": fmt.Sprint(e.PanicObj)}
+	}
+
+	pcs := callStackMethod(err, "StackTrace")
+	if pcs == nil {
+		pcs = callStackMethod(err, "Callers")
+	}
+	if pcs != nil {
+		for _, item := range parsePCs(pcs).items {
+			node.Stack = append(node.Stack,
+				strings.TrimSuffix(item.fn, "(...)")+" "+strings.TrimSpace(item.src))
+		}
+		if len(node.Stack) > 0 {
+			node.CallSite = strings.TrimSpace(parsePCs(pcs).items[0].src)
+		}
+	}
+
+	if decoded, ok := err.(*DecodedError); ok && len(decoded.errs) == 1 {
+		node.Wrapped = marshalErrorTree(decoded.errs[0])
+		return node
+	}
+	if multiErr, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, child := range multiErr.Unwrap() {
+			node.Combined = append(node.Combined, marshalErrorTree(child))
+		}
+		return node
+	}
+	node.Wrapped = marshalErrorTree(nextInErrorChain(err))
+	return node
+}
+
+// UnmarshalError deserializes error, serialized using MarshalError.
+//
+// Messages and structure of errors are preserved: errflow errors
+// (e.g. CombinedError, SuppressedError, IndexError) are restored with their types,
+// registered sentinels are restored as original instances,
+// and all other errors are restored as *DecodedError.
+//
+// Returns nil error for JSON null.
+func UnmarshalError(data []byte) (error, error) {
+	var node *errorJSON
+	if err := json.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	return node.toError(), nil
+}
+
+func (node *errorJSON) toError() error {
+	if node == nil {
+		return nil
+	}
+	if node.Sentinel != "" {
+		if sentinel, ok := lookupSentinel(node.Sentinel); ok {
+			return sentinel
+		}
+	}
+
+	wrapped := node.Wrapped.toError()
+	var combined []error
+	for _, child := range node.Combined {
+		combined = append(combined, child.toError())
+	}
+
+	switch node.Type {
+	case "errf.CombinedError":
+		return CombinedError{errs: combined}
+	}
+	if wrapped != nil {
+		switch node.Type {
+		case "*errf.SuppressedError":
+			var suppressed []error
+			for _, child := range node.Suppressed {
+				suppressed = append(suppressed, child.toError())
+			}
+			return &SuppressedError{Err: wrapped, Suppressed: suppressed}
+		case "*errf.LabeledError":
+			return &LabeledError{Label: fmt.Sprint(node.Fields["label"]), Err: wrapped}
+		case "*errf.IndexError":
+			if index, ok := node.Fields["index"].(float64); ok {
+				return &IndexError{Index: int(index), Err: wrapped}
+			}
+		case "*errf.StickyError":
+			if offset, ok := node.Fields["offset"].(float64); ok {
+				return &StickyError{Offset: int64(offset), Err: wrapped}
+			}
+		}
+	}
+
+	result := &DecodedError{
+		Message:  node.Message,
+		Type:     node.Type,
+		Fields:   node.Fields,
+		Stack:    node.Stack,
+		CallSite: node.CallSite,
+		errs:     combined,
+	}
+	if wrapped != nil {
+		result.errs = []error{wrapped}
+	}
+	return result
+}
+
+// DecodedError is an error restored by UnmarshalError.
+type DecodedError struct {
+	Message  string
+	Type     string
+	Fields   map[string]interface{}
+	Stack    []string
+	CallSite string
+
+	errs []error
+}
+
+func (e *DecodedError) Error() string {
+	return e.Message
+}
+
+// Format implements fmt.Formatter.
+func (e *DecodedError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*DecodedError) errfFormatted() {}
+
+// Unwrap returns restored wrapped errors.
+func (e *DecodedError) Unwrap() []error {
+	return e.errs
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *DecodedError) MarshalJSON() ([]byte, error) {
+	return MarshalError(e)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (cErr CombinedError) MarshalJSON() ([]byte, error) {
+	return MarshalError(cErr)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *SuppressedError) MarshalJSON() ([]byte, error) {
+	return MarshalError(e)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *StackError) MarshalJSON() ([]byte, error) {
+	return MarshalError(e)
+}
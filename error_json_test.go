@@ -0,0 +1,118 @@
+package errf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTestSentinel = errors.New("test sentinel")
+
+func init() {
+	RegisterSentinel("errf.errTestSentinel", errTestSentinel)
+}
+
+func roundTrip(t *testing.T, err error) error {
+	data, marshalErr := MarshalError(err)
+	assert.NoError(t, marshalErr)
+	result, unmarshalErr := UnmarshalError(data)
+	assert.NoError(t, unmarshalErr)
+	return result
+}
+
+func Test_MarshalError(t *testing.T) {
+	err := &IndexError{Index: 2, Err: fmt.Errorf("wrapped: %w", io.EOF)}
+
+	data, marshalErr := MarshalError(err)
+	assert.NoError(t, marshalErr)
+	assert.JSONEq(t, `{
+		"message": "index 2: wrapped: EOF",
+		"type": "*errf.IndexError",
+		"fields": {"index": 2},
+		"wrapped": {
+			"message": "wrapped: EOF",
+			"type": "*fmt.wrapError",
+			"wrapped": {"message": "EOF", "type": "*errors.errorString", "sentinel": "io.EOF"}
+		}
+	}`, string(data))
+}
+
+func Test_UnmarshalError(t *testing.T) {
+	err := roundTrip(t, &IndexError{Index: 2, Err: fmt.Errorf("wrapped: %w", errTestSentinel)})
+
+	assert.EqualError(t, err, "index 2: wrapped: test sentinel")
+	assert.True(t, errors.Is(err, errTestSentinel))
+	var indexErr *IndexError
+	assert.True(t, errors.As(err, &indexErr))
+	assert.Equal(t, 2, indexErr.Index)
+	var decodedErr *DecodedError
+	assert.True(t, errors.As(err, &decodedErr))
+	assert.Equal(t, "*fmt.wrapError", decodedErr.Type)
+}
+
+func Test_UnmarshalError_Nil(t *testing.T) {
+	data, err := MarshalError(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	assert.Nil(t, roundTrip(t, nil))
+
+	_, err = UnmarshalError([]byte("{"))
+	assert.Error(t, err)
+}
+
+func Test_UnmarshalError_CombinedAndSuppressed(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		defer CheckDeferErr(errorFn("close error"))
+		CheckErr(io.ErrUnexpectedEOF)
+		return nil
+	}
+	err := roundTrip(t, fn())
+	assert.EqualError(t, err, "combined error {unexpected EOF; close error}")
+	assert.Equal(t, 2, len(GetCombinedErrors(err)))
+	assert.Equal(t, io.ErrUnexpectedEOF, GetCombinedErrors(err)[0])
+
+	fn = func() (err error) {
+		defer IfError().AttachSuppressed().LogAlways().ThenAssignTo(&err)
+		defer CheckDeferErr(errorFn("close error"))
+		With(Label("label")).CheckErr(errTestSentinel)
+		return nil
+	}
+	defer SetLogFn(func(logMessage *LogMessage) {}).ThenRestore()
+	err = roundTrip(t, fn())
+	assert.EqualError(t, err, "test sentinel")
+	assert.True(t, errors.Is(err, errTestSentinel))
+	assert.Equal(t, "label", LabelOf(err))
+	assert.Equal(t, 1, len(Suppressed(err)))
+	assert.EqualError(t, Suppressed(err)[0], "close error")
+}
+
+func Test_MarshalError_Stack(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().Apply(WrapperAddStack).ThenAssignTo(&err)
+		CheckErr(fmt.Errorf("error"))
+		return nil
+	}
+
+	stackErr := fn()
+	var node errorJSON
+	data, err := json.Marshal(stackErr)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &node))
+	assert.Equal(t, "*errf.StackError", node.Type)
+	assert.Contains(t, node.Stack[0], "errf.Test_MarshalError_Stack")
+	assert.Contains(t, node.CallSite, "error_json_test.go")
+
+	decoded := roundTrip(t, stackErr)
+	var decodedErr *DecodedError
+	assert.True(t, errors.As(decoded, &decodedErr))
+	assert.Equal(t, node.CallSite, decodedErr.CallSite)
+
+	data2, err := json.Marshal(decoded)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(data2))
+}
@@ -0,0 +1,204 @@
+package errf
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FormatOption configures errf.Format(...) output.
+type FormatOption func(opts *formatOptions)
+
+type formatOptions struct {
+	color    bool
+	stacks   bool
+	maxDepth int
+}
+
+// FormatColor configures errf.Format(...) to use ANSI colors (e.g. for CLI output).
+func FormatColor(opts *formatOptions) {
+	opts.color = true
+}
+
+// FormatStacks configures errf.Format(...) to include stack traces.
+func FormatStacks(opts *formatOptions) {
+	opts.stacks = true
+}
+
+// FormatMaxDepth configures errf.Format(...) to render only maxDepth levels of error tree.
+func FormatMaxDepth(maxDepth int) FormatOption {
+	return func(opts *formatOptions) {
+		opts.maxDepth = maxDepth
+	}
+}
+
+const (
+	ansiReset = "\033[0m"
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiCyan  = "\033[36m"
+)
+
+// Format renders err as an indented tree.
+//
+// Tree contains each wrap layer, each error of combined errors, suppressed errors,
+// labels, indexes and other fields of errflow errors, and call sites.
+// Transparent layers (e.g. labeled errors) don't repeat the message, except for the root.
+//
+// errflow error types (e.g. CombinedError, SuppressedError) use this format
+// with stack traces (see FormatStacks) for "%+v".
+//
+// Example:
+//  fmt.Println(errf.Format(err, errf.FormatMaxDepth(5)))
+//
+// Output:
+//  combined error (2 errors) [errf.CombinedError]
+//    combined: index 1: strconv.Atoi: parsing "x": invalid syntax [*errf.IndexError index=1]
+//      caused by: strconv.Atoi: parsing "x": invalid syntax [*strconv.NumError]
+//        caused by: invalid syntax [*errors.errorString]
+//    combined: close error [*errors.errorString]
+func Format(err error, options ...FormatOption) string {
+	if err == nil {
+		return "<nil>"
+	}
+	opts := &formatOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	var buffer strings.Builder
+	opts.writeNode(&buffer, marshalErrorTree(err), "", "", 0)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func (opts *formatOptions) colored(color string, s string) string {
+	if !opts.color || s == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func (opts *formatOptions) writeNode(w io.Writer, node *errorJSON, indent string, relation string, depth int) {
+	if opts.maxDepth > 0 && depth >= opts.maxDepth {
+		_, _ = fmt.Fprintf(w, "%s%s...\n", indent, opts.colored(ansiDim, relation))
+		return
+	}
+
+	message := node.Message
+	if len(node.Combined) > 0 {
+		message = fmt.Sprintf("combined error (%d errors)", len(node.Combined))
+	} else if depth > 0 && node.Wrapped != nil && node.Wrapped.Message == node.Message {
+		message = ""
+	}
+	message = strings.ReplaceAll(message, "\n", "\n"+indent+"  ")
+	if node.Wrapped == nil && len(node.Combined) == 0 {
+		message = opts.colored(ansiRed, message)
+	}
+
+	annotations := []string{node.Type}
+	if node.Sentinel != "" {
+		annotations = []string{node.Sentinel}
+	}
+	var keys []string
+	for key := range node.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		annotations = append(annotations, fmt.Sprintf("%s=%v", key, node.Fields[key]))
+	}
+	if node.CallSite != "" {
+		annotations = append(annotations, "at "+node.CallSite)
+	}
+
+	line := strings.TrimSpace(message + " " + opts.colored(ansiCyan, "["+strings.Join(annotations, " ")+"]"))
+	_, _ = fmt.Fprintf(w, "%s%s%s\n", indent, opts.colored(ansiDim, relation), line)
+	if opts.stacks {
+		for _, frame := range node.Stack {
+			_, _ = fmt.Fprintf(w, "%s    %s\n", indent, opts.colored(ansiDim, frame))
+		}
+	}
+
+	childIndent := indent + "  "
+	for _, child := range node.Combined {
+		opts.writeNode(w, child, childIndent, "combined: ", depth+1)
+	}
+	for _, child := range node.Suppressed {
+		opts.writeNode(w, child, childIndent, "suppressed: ", depth+1)
+	}
+	if node.Wrapped != nil {
+		opts.writeNode(w, node.Wrapped, childIndent, "caused by: ", depth+1)
+	}
+}
+
+// Format implements fmt.Formatter.
+func (cErr CombinedError) Format(s fmt.State, verb rune) {
+	formatError(cErr, s, verb)
+}
+
+func (CombinedError) errfFormatted() {}
+
+// errfFormattedError is implemented by errflow error types,
+// which use formatError to implement fmt.Formatter.
+type errfFormattedError interface {
+	error
+	errfFormatted()
+}
+
+// formatError implements fmt.Formatter for errflow error types:
+//  * "%+v" renders errf.Format(...) tree, including stack traces;
+//  * "%#v" renders Go-syntax representation of err;
+//  * "%v", "%s", "%q", "%x" and "%X" format err.Error() string;
+//  * other verbs are reported same as fmt does, e.g. "%!d(*errf.IndexError=index 1: EOF)".
+func formatError(err error, s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, Format(err, FormatStacks))
+			return
+		}
+		if s.Flag('#') {
+			formatGoSyntax(err, s)
+			return
+		}
+		fallthrough
+	case 's', 'q', 'x', 'X':
+		_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), err.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(%T=%s)", verb, err, err.Error())
+	}
+}
+
+// formatGoSyntax writes "%#v" representation of err, same as fmt does for types
+// without Format method (calling fmt with err itself would call formatError again).
+func formatGoSyntax(err error, s fmt.State) {
+	value := reflect.ValueOf(err)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			_, _ = fmt.Fprintf(s, "(%T)(nil)", err)
+			return
+		}
+		_, _ = io.WriteString(s, "&")
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		_, _ = fmt.Fprintf(s, "%s(%q)", value.Type().String(), err.Error())
+		return
+	}
+	_, _ = io.WriteString(s, value.Type().String()+"{")
+	for i := 0; i < value.NumField(); i++ {
+		if i > 0 {
+			_, _ = io.WriteString(s, ", ")
+		}
+		_, _ = io.WriteString(s, value.Type().Field(i).Name+":")
+		field := value.Field(i)
+		if field.CanInterface() {
+			_, _ = fmt.Fprintf(s, "%#v", field.Interface())
+		} else {
+			// Unexported fields are printed by fmt without calling their methods.
+			_, _ = fmt.Fprintf(s, "%#v", field)
+		}
+	}
+	_, _ = io.WriteString(s, "}")
+}
@@ -0,0 +1,138 @@
+package errf
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Format(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		defer CheckDeferErr(errorFn("close error"))
+		Map([]string{"1", "x"}, strconv.Atoi)
+		return nil
+	}
+
+	err := fn()
+	expected := "combined error (2 errors) [errf.CombinedError]\n" +
+		`  combined: index 1: strconv.Atoi: parsing "x": invalid syntax [*errf.IndexError index=1]` + "\n" +
+		`    caused by: strconv.Atoi: parsing "x": invalid syntax [*strconv.NumError]` + "\n" +
+		"      caused by: invalid syntax [*errors.errorString]\n" +
+		"  combined: close error [*errors.errorString]"
+	assert.Equal(t, expected, Format(err))
+	assert.Equal(t, expected, fmt.Sprintf("%+v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+}
+
+func Test_Format_TransparentLayers(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		With(Label("read"), WrapperFmtErrorw("wrapped")).CheckErr(io.EOF)
+		return nil
+	}
+
+	assert.Equal(t, "wrapped: EOF [*errf.LabeledError label=read]\n"+
+		"  caused by: wrapped: EOF [*fmt.wrapError]\n"+
+		"    caused by: EOF [io.EOF]", Format(fn()))
+	assert.Equal(t, "index 0: x [*errf.IndexError index=0]\n"+
+		"  caused by: [*errf.LabeledError label=l]\n"+
+		"    caused by: x [*errors.errorString]",
+		Format(&IndexError{Err: &LabeledError{Label: "l", Err: fmt.Errorf("x")}}))
+}
+
+func Test_Format_MaxDepth(t *testing.T) {
+	err := fmt.Errorf("a: %w", fmt.Errorf("b: %w", io.EOF))
+
+	assert.Equal(t, "a: b: EOF [*fmt.wrapError]\n"+
+		"  caused by: b: EOF [*fmt.wrapError]\n"+
+		"    caused by: ...", Format(err, FormatMaxDepth(2)))
+	assert.Equal(t, "<nil>", Format(nil))
+}
+
+func Test_Format_ColorAndStacks(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().Apply(WrapperAddStack).ThenAssignTo(&err)
+		CheckErr(fmt.Errorf("error"))
+		return nil
+	}
+
+	formatted := Format(fn(), FormatColor, FormatStacks)
+	lines := strings.Split(formatted, "\n")
+	assert.Contains(t, lines[0], "\033[36m[*errf.StackError at ")
+	assert.Contains(t, lines[0], "format_test.go")
+	assert.Contains(t, lines[1], "errf.Test_Format_ColorAndStacks")
+	assert.Contains(t, formatted, "\033[2mcaused by: \033[0m\033[31merror\033[0m")
+}
+
+func Test_Format_ErrflowTypes(t *testing.T) {
+	decoded, unmarshalErr := UnmarshalError([]byte(`{"message":"decoded","type":"*os.PathError"}`))
+	assert.NoError(t, unmarshalErr)
+
+	for _, test := range []struct {
+		err      error
+		expected string
+	}{
+		{&IndexError{Index: 1, Err: io.EOF}, "index 1: EOF [*errf.IndexError index=1]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{&NotFoundError{Key: "k", Message: "key not found"}, "key not found [*errf.NotFoundError key=k]"},
+		{&TypeAssertionError{Value: 1, Type: reflect.TypeOf("")},
+			"type assertion failed: int is not string [*errf.TypeAssertionError]"},
+		{&CloseTimeoutError{Timeout: time.Second},
+			"close timed out after 1s [*errf.CloseTimeoutError timeout=1s]"},
+		{decoded, "decoded [*os.PathError]"},
+		{PanicErr{PanicObj: "oops"}, "panic: oops [errf.PanicErr panic=oops]"},
+		{&LabeledError{Label: "read", Err: io.EOF}, "EOF [*errf.LabeledError label=read]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{markLogged(io.EOF), "EOF [io.EOF]"},
+		{&NetTimeoutError{Err: io.EOF}, "EOF [*errf.NetTimeoutError]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{&NetTemporaryError{Err: io.EOF}, "EOF [*errf.NetTemporaryError]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{&StickyError{Offset: 10, Err: io.EOF}, "at byte offset 10: EOF [*errf.StickyError offset=10]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{&SuppressedError{Err: io.EOF, Suppressed: []error{io.ErrClosedPipe}}, "EOF [*errf.SuppressedError]\n" +
+			"  suppressed: io: read/write on closed pipe [*errors.errorString]\n" +
+			"  caused by: EOF [io.EOF]"},
+		{CombinedError{errs: []error{io.EOF, io.ErrClosedPipe}}, "combined error (2 errors) [errf.CombinedError]\n" +
+			"  combined: EOF [io.EOF]\n" +
+			"  combined: io: read/write on closed pipe [*errors.errorString]"},
+	} {
+		assert.Implements(t, (*errfFormattedError)(nil), test.err)
+		assert.Equal(t, test.expected, fmt.Sprintf("%+v", test.err))
+		assert.Equal(t, test.err.Error(), fmt.Sprintf("%v", test.err))
+		assert.Equal(t, test.err.Error(), fmt.Sprintf("%s", test.err))
+		assert.Equal(t, strconv.Quote(test.err.Error()), fmt.Sprintf("%q", test.err))
+	}
+}
+
+func Test_Format_StackError(t *testing.T) {
+	err := WrapperAddStack(DefaultErrflow).wrapper(io.EOF)
+
+	formatted := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	assert.Contains(t, formatted[0], "EOF [*errf.StackError at ")
+	assert.Contains(t, formatted[0], "format_test.go")
+	assert.Contains(t, formatted[1], "errf.Test_Format_StackError")
+	assert.Equal(t, "  caused by: EOF [io.EOF]", formatted[len(formatted)-1])
+	assert.Equal(t, "EOF", fmt.Sprintf("%v", err))
+}
+
+func Test_Format_Verbs(t *testing.T) {
+	err := &IndexError{Index: 1, Err: io.EOF}
+
+	assert.Equal(t, `&errf.IndexError{Index:1, Err:&errors.errorString{s:"EOF"}}`, fmt.Sprintf("%#v", err))
+	assert.Equal(t, `errf.PanicErr{PanicObj:"oops"}`, fmt.Sprintf("%#v", PanicErr{PanicObj: "oops"}))
+	assert.Equal(t, `&errf.LabeledError{Label:"read", Err:&errf.IndexError{Index:1, Err:&errors.errorString{s:"EOF"}}}`,
+		fmt.Sprintf("%#v", &LabeledError{Label: "read", Err: err}))
+	assert.Equal(t, `(*errf.IndexError)(nil)`, fmt.Sprintf("%#v", (*IndexError)(nil)))
+	assert.Equal(t, fmt.Sprintf("%x", err.Error()), fmt.Sprintf("%x", err))
+	assert.Equal(t, "   index 1: EOF", fmt.Sprintf("%15s", err))
+	assert.Equal(t, "%!d(*errf.IndexError=index 1: EOF)", fmt.Sprintf("%d", err))
+}
@@ -0,0 +1,91 @@
+package errf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Error describes an error, which escaped its scope: Check* function failed
+// in a function without IfError() handler.
+func (t errflowThrow) Error() string {
+	if len(t.items) == 0 {
+		return "errflow: unhandled error"
+	}
+	item := t.items[0]
+	api, caller := errflowCaller(item.pcs)
+
+	var buffer strings.Builder
+	_, _ = fmt.Fprintf(&buffer, "errflow: unhandled error %s", errString(item.err))
+	if api != "" {
+		_, _ = fmt.Fprintf(&buffer, " from errf.%s", api)
+	}
+	if caller.File != "" {
+		_, _ = fmt.Fprintf(&buffer, " at %s:%d", caller.File, caller.Line)
+	}
+	if len(t.items) > 1 {
+		var others []string
+		for _, other := range t.items[1:] {
+			others = append(others, errString(other.err))
+		}
+		_, _ = fmt.Fprintf(&buffer, " (also: %s)", strings.Join(others, ", "))
+	}
+	fn := "the function"
+	if caller.Function != "" {
+		fn = caller.Function
+	}
+	_, _ = fmt.Fprintf(&buffer,
+		"; Check* functions require 'defer errf.IfError()...' handler in the same function, is it missing in %s?",
+		fn)
+	return buffer.String()
+}
+
+func (t errflowThrow) String() string {
+	return t.Error()
+}
+
+// Guard is a last resort handler for errors, which escaped their scope:
+// Check* function failed in a function without IfError() handler.
+// Such errors crash the program (similar to unhandled panics).
+//
+// Guard converts escaped errors into logged errors (see SetLogFn).
+// Other panics are not affected.
+//
+// Guard should only be used directly in defer statements,
+// typically at goroutine entry points.
+//
+// Example:
+//  go func() {
+//  	defer errf.Guard()
+//
+//  	processQueue(queue)
+//  }()
+func Guard() {
+	recoverObj := recover()
+	if recoverObj == nil {
+		return
+	}
+	errflowThrowObj, ok := recoverObj.(errflowThrow)
+	if !ok {
+		panic(recoverObj)
+	}
+	var pcs []uintptr
+	if len(errflowThrowObj.items) > 0 {
+		pcs = errflowThrowObj.items[0].pcs
+	}
+	globalLogFn(&LogMessage{
+		Format: "%s",
+		A:      []interface{}{errflowThrowObj.Error()},
+		Stack: func() string {
+			return parsePCs(pcs).String()
+		},
+		Tags: []string{"errorflow", "escaped-error"},
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return strconv.Quote(err.Error())
+}
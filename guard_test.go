@@ -0,0 +1,93 @@
+package errf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func escapedPanic(fn func()) (recoverObj interface{}) {
+	defer func() {
+		recoverObj = recover()
+	}()
+	fn()
+	return nil
+}
+
+func Test_errflowThrow_Error(t *testing.T) {
+	defer SetNoopValidator().ThenRestore()
+
+	recoverObj := escapedPanic(func() {
+		CheckErr(fmt.Errorf("error1"))
+	})
+
+	err, ok := recoverObj.(error)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(err.Error(), "errflow: unhandled error \"error1\" from errf.CheckErr at "), err.Error())
+	assert.Contains(t, err.Error(), "guard_test.go:")
+	assert.True(t, strings.HasSuffix(err.Error(),
+		"; Check* functions require 'defer errf.IfError()...' handler in the same function,"+
+			" is it missing in github.com/serhiy-t/errf.Test_errflowThrow_Error.func1?"), err.Error())
+	assert.Equal(t, err.Error(), fmt.Sprint(recoverObj))
+}
+
+func Test_errflowThrow_Error_multipleErrors(t *testing.T) {
+	defer SetNoopValidator().ThenRestore()
+
+	recoverObj := escapedPanic(func() {
+		defer Std.CheckInt(0, fmt.Errorf("error2"))
+		CheckErr(fmt.Errorf("error1"))
+	})
+
+	assert.Contains(t, fmt.Sprint(recoverObj), "errflow: unhandled error \"error1\" from errf.CheckErr at ")
+	assert.Contains(t, fmt.Sprint(recoverObj), " (also: \"error2\"); ")
+}
+
+func Test_Guard(t *testing.T) {
+	defer SetNoopValidator().ThenRestore()
+	var messages []*LogMessage
+	defer SetLogFn(func(logMessage *LogMessage) {
+		messages = append(messages, logMessage)
+	}).ThenRestore()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer Guard()
+
+		CheckErr(fmt.Errorf("error1"))
+	}()
+	wg.Wait()
+
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, []string{"errorflow", "escaped-error"}, messages[0].Tags)
+		assert.Contains(t, fmt.Sprintf(messages[0].Format, messages[0].A...), "errflow: unhandled error \"error1\"")
+		assert.Contains(t, messages[0].Stack(), "guard_test.go:")
+	}
+}
+
+func Test_Guard_noError(t *testing.T) {
+	var messages []*LogMessage
+	defer SetLogFn(func(logMessage *LogMessage) {
+		messages = append(messages, logMessage)
+	}).ThenRestore()
+
+	func() {
+		defer Guard()
+	}()
+
+	assert.Empty(t, messages)
+}
+
+func Test_Guard_unrelatedPanic(t *testing.T) {
+	assert.PanicsWithValue(t, "panic1", func() {
+		defer Guard()
+		panic("panic1")
+	})
+}
@@ -57,6 +57,13 @@ func (p PanicErr) Error() string {
 	return fmt.Sprintf("panic: %v", p.PanicObj)
 }
 
+// Format implements fmt.Formatter.
+func (p PanicErr) Format(s fmt.State, verb rune) {
+	formatError(p, s, verb)
+}
+
+func (PanicErr) errfFormatted() {}
+
 // Always handler is always executed.
 // Error is not sent to the callback.
 //
@@ -94,6 +101,22 @@ func (h *InterimHandler) OnErrIs(targetErr error, errFn func()) {
 	})
 }
 
+// OnLabel handler is executed in case of error triggered by one of "Check*" functions
+// and first encountered error was produced by a Check* function configured with errf.Label(label).
+//
+// Example:
+//  writer := errf.Io.With(errf.Label("open-dst")).CheckWriteCloser(os.Create(dst))
+//  defer errf.Handle().OnLabel("open-dst", func(err error) {
+//  	// This callback only will be executed if os.Create(dst) failed.
+//  })
+func (h *InterimHandler) OnLabel(label string, errFn ErrorActionFn) {
+	h.handle(recover(), handleCondition{onError: true}, func(err error) {
+		if LabelOf(err) == label {
+			errFn(err)
+		}
+	})
+}
+
 func verifyErrFnType(argument string, errFn interface{}) {
 	t := reflect.TypeOf(errFn)
 	if t.Kind() != reflect.Func {
@@ -120,9 +143,11 @@ func verifyErrFnType(argument string, errFn interface{}) {
 //  	// error has type of net.Error.
 //  })
 func (h *InterimHandler) OnErrAs(errFn interface{}) {
-	globalErrflowValidator.custom(func() {
-		verifyErrFnType("OnErrAs: errFn", errFn)
-	})
+	if validatorEnabled {
+		globalErrflowValidator.custom(func() {
+			verifyErrFnType("OnErrAs: errFn", errFn)
+		})
+	}
 	h.handle(recover(), handleCondition{onError: true}, func(err error) {
 		errFnValue := reflect.ValueOf(errFn)
 		errValue := reflect.New(errFnValue.Type().In(0))
@@ -232,7 +257,7 @@ func (h *InterimHandler) handle(
 	condition handleCondition,
 	fn ErrorActionFn,
 ) {
-	if condition.onError && !condition.notValidate {
+	if validatorEnabled && condition.onError && !condition.notValidate {
 		if isUnrelatedPanic(recoverObj) {
 			globalErrflowValidator.markPanic()
 		}
@@ -246,22 +271,36 @@ func (h *InterimHandler) handle(
 			item := errflowThrowObj.items[0]
 			ef := item.ef
 			err := item.err
+			if scope := handlersScope(); scope != nil {
+				ef = ef.With(scope.options...)
+			}
 			ef.applyDeferredOptions()
 			if ef.wrapper != nil && err != nil {
 				err = ef.wrapper(err)
 			}
+			err = ef.applyLabel(err)
 			defer handleDoPanicOnError(errflowThrowObj)
 			if condition.onError {
+				if globalTracer != nil {
+					traceHandle(err)
+				}
 				fn(err)
 			}
 		} else {
 			defer handleDoPanicOnPanic(recoverObj)
 			if condition.onPanic {
-				fn(PanicErr{PanicObj: recoverObj})
+				panicErr := PanicErr{PanicObj: recoverObj}
+				if globalTracer != nil {
+					traceHandle(panicErr)
+				}
+				fn(panicErr)
 			}
 		}
 	} else {
 		if condition.onSuccess {
+			if globalTracer != nil {
+				traceHandle(nil)
+			}
 			fn(nil)
 		}
 	}
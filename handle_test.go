@@ -608,6 +608,9 @@ func Test_Handler_NestedCheck_Panic_Panic(t *testing.T) {
 }
 
 func Test_Handler_DoubleNestedCheck(t *testing.T) {
+	if !validatorEnabled {
+		t.Skip("validator is compiled out")
+	}
 	fn := func() (err error) {
 		defer IfError().ReturnWrapped().ThenAssignTo(&err)
 
@@ -624,3 +627,63 @@ func Test_Handler_DoubleNestedCheck(t *testing.T) {
 		_ = fn()
 	})
 }
+
+var errHandlersSentinel = fmt.Errorf("sentinel")
+
+func Test_Handler_ApplyToHandlers(t *testing.T) {
+	var handlerErr error
+	isCalled := false
+	fn := func() (err error) {
+		defer IfError().Apply(WrapperFmtErrorf("%w: %w", errHandlersSentinel, OriginalErr)).
+			ApplyToHandlers().ThenAssignTo(&err)
+
+		defer Handle().OnErr(func(err error) { handlerErr = err })
+		defer Handle().OnErrIs(errHandlersSentinel, func() { isCalled = true })
+
+		With(WrapperFmtErrorw("check")).CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "sentinel: check: error1")
+	assert.EqualError(t, handlerErr, err.Error())
+	assert.True(t, isCalled)
+	assert.Empty(t, handlersScopes.byGoroutine)
+}
+
+func Test_Handler_ApplyToHandlers_default(t *testing.T) {
+	var handlerErr error
+	fn := func() (err error) {
+		defer IfError().Apply(WrapperFmtErrorw("scope")).ThenAssignTo(&err)
+
+		defer Handle().OnErr(func(err error) { handlerErr = err })
+
+		With(WrapperFmtErrorw("check")).CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "scope: check: error1")
+	assert.EqualError(t, handlerErr, "check: error1")
+}
+
+func Test_Handler_ApplyToHandlers_nestedFunction(t *testing.T) {
+	var handlerErr error
+	inner := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		defer Handle().OnErr(func(err error) { handlerErr = err })
+
+		CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().Apply(WrapperFmtErrorw("outer")).ApplyToHandlers().ThenAssignTo(&err)
+
+		CheckErr(inner())
+		return nil
+	}
+
+	assert.EqualError(t, outer(), "outer: error1")
+	assert.EqualError(t, handlerErr, "error1")
+	assert.Empty(t, handlersScopes.byGoroutine)
+}
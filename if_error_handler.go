@@ -14,15 +14,37 @@ package errf
 //    // ...
 //  }
 func IfError() *IfErrorHandler {
-	globalErrflowValidator.enter()
-	return &IfErrorHandler{}
+	return &IfErrorHandler{trace: enterIfError()}
+}
+
+// enterIfError registers a new IfError() handler scope.
+// It returns scope trace, if tracing is enabled.
+func enterIfError() *Trace {
+	if validatorEnabled {
+		globalErrflowValidator.enter()
+	}
+	if globalTracer != nil {
+		return enterTrace("")
+	}
+	return nil
 }
 
 // IfErrorHandler configures errorflow error handling behavior in a scope of a function.
 //
 // Should be created only via IfError() method.
 type IfErrorHandler struct {
-	options []ErrflowOption
+	options  []ErrflowOption
+	callback bool
+	owning   bool
+	owned    []ownedCloser
+	trace    *Trace
+
+	// owningFn is a function, which resources registered using errf.Own belong to (see Owning).
+	owningFn string
+
+	// handlersFn is a function, which Handle() callbacks receive errors
+	// processed with IfErrorHandler options (see ApplyToHandlers).
+	handlersFn string
 }
 
 // ThenAssignTo assigns resulting error to outErr (only if non-nil).
@@ -40,15 +62,22 @@ type IfErrorHandler struct {
 func (c *IfErrorHandler) ThenAssignTo(outErr *error) {
 	c.catch(recover(), func(err error) {
 		if *outErr != nil {
+			c.trace.add(TraceAssign, "", err, "error %s replaces directly returned error %s",
+				errString(err), errString(*outErr))
 			ef := With(c.options...)
 			ef.applyDeferredOptions()
-			if ef.logStrategy == logStrategyAlways || ef.logStrategy == logStrategyIfSuppressed {
+			if (ef.logStrategy == logStrategyAlways || ef.logStrategy == logStrategyIfSuppressed) &&
+				ef.shouldLog(*outErr) {
 				globalLogFn(&LogMessage{
 					Format: "%s",
 					A:      []interface{}{(*outErr).Error()},
-					Stack:  getStringErrorStackTraceFn(),
+					Stack:  getStringErrorStackTraceFnFor(*outErr),
 					Tags:   []string{"errorflow", "suppressed-external-error"},
 				})
+				*outErr = markLogged(*outErr)
+			}
+			if ef.attachSuppressed {
+				err = attachSuppressed(err, []error{*outErr})
 			}
 		}
 		*outErr = err
@@ -92,6 +121,11 @@ func (c *IfErrorHandler) ReturnCombined() *IfErrorHandler {
 	return c.Apply(ReturnStrategyCombined)
 }
 
+// AttachSuppressed is an alias for Apply(AttachSuppressed).
+func (c *IfErrorHandler) AttachSuppressed() *IfErrorHandler {
+	return c.Apply(AttachSuppressed)
+}
+
 // LogAlways is an alias for Apply(LogStrategyAlways).
 func (c *IfErrorHandler) LogAlways() *IfErrorHandler {
 	return c.Apply(LogStrategyAlways)
@@ -102,6 +136,11 @@ func (c *IfErrorHandler) LogIfSuppressed() *IfErrorHandler {
 	return c.Apply(LogStrategyIfSuppressed)
 }
 
+// LogForce is an alias for Apply(LogForce).
+func (c *IfErrorHandler) LogForce() *IfErrorHandler {
+	return c.Apply(LogForce)
+}
+
 // LogNever is an alias for Apply(LogStrategyNever).
 func (c *IfErrorHandler) LogNever() *IfErrorHandler {
 	return c.Apply(LogStrategyNever)
@@ -125,6 +164,44 @@ func (c *IfErrorHandler) Apply(options ...ErrflowOption) *IfErrorHandler {
 	return c
 }
 
+// ApplyToHandlers configures Handle() callbacks in the same function to receive errors
+// processed with IfErrorHandler options (e.g. wrappers and labels applied via Apply(...)).
+//
+// By default, Handle() callbacks receive errors processed only with Check* function options,
+// because IfErrorHandler options are applied when the function returns.
+//
+// Example:
+//  func example(filename string) (err error) {
+//  	defer errf.IfError().Apply(errf.WrapperFmtErrorf("%w: %w", ErrStorage, errf.OriginalErr)).
+//  		ApplyToHandlers().ThenAssignTo(&err)
+//
+//  	defer errf.Handle().OnErrIs(ErrStorage, func() {
+//  		// This callback is executed, because ErrStorage is added by IfError() wrapper.
+//  	})
+//
+//  	errf.CheckErr(os.Remove(filename))
+//  	return nil
+//  }
+func (c *IfErrorHandler) ApplyToHandlers() *IfErrorHandler {
+	if c.handlersFn == "" {
+		c.handlersFn = callerFunction()
+		handlersScopes.push(c)
+	}
+	return c
+}
+
+var handlersScopes = newGoroutineScopes[*IfErrorHandler]()
+
+// handlersScope returns IfErrorHandler configured with ApplyToHandlers() in the function,
+// which called errflow, or nil.
+func handlersScope() *IfErrorHandler {
+	scope, ok := handlersScopes.top()
+	if !ok || scope.handlersFn != callerFunction() {
+		return nil
+	}
+	return scope
+}
+
 func isUnrelatedPanic(recoverObj interface{}) bool {
 	if recoverObj != nil {
 		_, ok := recoverObj.(errflowThrow)
@@ -136,60 +213,110 @@ func isUnrelatedPanic(recoverObj interface{}) bool {
 }
 
 func (c *IfErrorHandler) catch(recoverObj interface{}, fn ErrorActionFn) {
-	if isUnrelatedPanic(recoverObj) {
-		globalErrflowValidator.markPanic()
+	if validatorEnabled {
+		if isUnrelatedPanic(recoverObj) {
+			globalErrflowValidator.markPanic()
+		}
+		if c.callback {
+			globalErrflowValidator.leaveCallback()
+		} else {
+			globalErrflowValidator.leave()
+		}
+	}
+	if c.trace != nil {
+		traceScopes.remove(c.trace)
+		defer finishTrace(c.trace)
+	}
+	if c.handlersFn != "" {
+		handlersScopes.remove(c)
 	}
-	globalErrflowValidator.leave()
 
+	var items []errflowThrowItem
 	if recoverObj != nil {
 		errflowThrow, ok := recoverObj.(errflowThrow)
-		if ok {
-			var currItem errflowThrowItem
-			for _, item := range errflowThrow.items {
-				item.ef = item.ef.With(c.options...)
-				item.ef.applyDeferredOptions()
-				if item.ef.wrapper != nil && item.err != nil {
-					item.err = item.ef.wrapper(item.err)
-				}
-
-				if item.ef.logStrategy == logStrategyAlways {
-					globalLogFn(&LogMessage{
-						Format: "%s",
-						A:      []interface{}{item.err.Error()},
-						Stack:  getStringErrorStackTraceFn(),
-						Tags:   []string{"errorflow", "error"},
-					})
-				}
-
-				if !(currItem.ef == nil && currItem.err == nil) {
-					supp1, supp2, newErr := getReturnStrategyImpl(item.ef.returnStrategy)(currItem.err, item.err)
-
-					if supp1 && currItem.ef.logStrategy == logStrategyIfSuppressed {
-						globalLogFn(&LogMessage{
-							Format: "%s",
-							A:      []interface{}{currItem.err.Error()},
-							Stack:  getStringErrorStackTraceFn(),
-							Tags:   []string{"errorflow", "suppressed-error"},
-						})
-					}
-					if supp2 && item.ef.logStrategy == logStrategyIfSuppressed {
-						globalLogFn(&LogMessage{
-							Format: "%s",
-							A:      []interface{}{item.err.Error()},
-							Stack:  getStringErrorStackTraceFn(),
-							Tags:   []string{"errorflow", "suppressed-error"},
-						})
-					}
-
-					currItem.err = newErr
-					currItem.ef = item.ef
-				} else {
-					currItem = item
-				}
+		if !ok {
+			if c.owning {
+				c.closeOwned(true)
 			}
-			fn(currItem.err)
-		} else {
+			c.trace.add(TraceLeave, "", nil, "panic: %v", recoverObj)
 			panic(recoverObj)
 		}
+		items = errflowThrow.items
+	}
+	if c.owning {
+		items = append(items, c.closeOwned(len(items) > 0)...)
+	}
+	if len(items) > 0 {
+		err := c.combine(items)
+		fn(err)
+		c.trace.add(TraceLeave, "", err, "error %s", errString(err))
+	} else {
+		c.trace.add(TraceLeave, "", nil, "success")
+	}
+}
+
+func (c *IfErrorHandler) combine(items []errflowThrowItem) error {
+	var currItem errflowThrowItem
+	var suppressed []error
+	for _, item := range items {
+		item.ef = item.ef.With(c.options...)
+		item.ef.applyDeferredOptions()
+		if item.ef.wrapper != nil && item.err != nil {
+			wrappedErr := item.ef.wrapper(item.err)
+			c.trace.add(TraceWrap, "", wrappedErr, "wrapper: %s -> %s", errString(item.err), errString(wrappedErr))
+			item.err = wrappedErr
+		}
+		if item.ef.label != "" && item.err != nil {
+			c.trace.add(TraceWrap, "", item.err, "label %q", item.ef.label)
+		}
+		item.err = item.ef.applyLabel(item.err)
+
+		if item.ef.logStrategy == logStrategyAlways && item.ef.shouldLog(item.err) {
+			globalLogFn(&LogMessage{
+				Format: "%s",
+				A:      []interface{}{item.err.Error()},
+				Stack:  getStringErrorStackTraceFnFor(item.err),
+				Tags:   appendLabelTag([]string{"errorflow", "error"}, item.err),
+			})
+			item.err = markLogged(item.err)
+		}
+
+		if !(currItem.ef == nil && currItem.err == nil) {
+			supp1, supp2, newErr := getReturnStrategyImpl(item.ef.returnStrategy)(currItem.err, item.err)
+			c.trace.add(TraceCombine, "", newErr, "%s(%s, %s) = %s, suppressed: %t, %t",
+				returnStrategyName(item.ef.returnStrategy), errString(currItem.err), errString(item.err),
+				errString(newErr), supp1, supp2)
+
+			if supp1 && currItem.ef.logStrategy == logStrategyIfSuppressed && currItem.ef.shouldLog(currItem.err) {
+				globalLogFn(&LogMessage{
+					Format: "%s",
+					A:      []interface{}{currItem.err.Error()},
+					Stack:  getStringErrorStackTraceFnFor(currItem.err),
+					Tags:   appendLabelTag([]string{"errorflow", "suppressed-error"}, currItem.err),
+				})
+				currItem.err = markLogged(currItem.err)
+			}
+			if supp2 && item.ef.logStrategy == logStrategyIfSuppressed && item.ef.shouldLog(item.err) {
+				globalLogFn(&LogMessage{
+					Format: "%s",
+					A:      []interface{}{item.err.Error()},
+					Stack:  getStringErrorStackTraceFnFor(item.err),
+					Tags:   appendLabelTag([]string{"errorflow", "suppressed-error"}, item.err),
+				})
+				item.err = markLogged(item.err)
+			}
+			if supp1 && currItem.ef.attachSuppressed {
+				suppressed = append(suppressed, currItem.err)
+			}
+			if supp2 && item.ef.attachSuppressed {
+				suppressed = append(suppressed, item.err)
+			}
+
+			currItem.err = newErr
+			currItem.ef = item.ef
+		} else {
+			currItem = item
+		}
 	}
+	return attachSuppressed(currItem.err, suppressed)
 }
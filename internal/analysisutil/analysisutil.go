@@ -0,0 +1,128 @@
+// Package analysisutil contains helpers shared by errflow analyzers.
+package analysisutil
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// ErrfPath is errflow package import path.
+const ErrfPath = "github.com/serhiy-t/errf"
+
+// Function is either *ast.FuncDecl or *ast.FuncLit.
+type Function struct {
+	Type *ast.FuncType
+	Body *ast.BlockStmt
+}
+
+// EnclosingFunc returns innermost function in inspector stack.
+func EnclosingFunc(stack []ast.Node) *Function {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch fn := stack[i].(type) {
+		case *ast.FuncDecl:
+			if fn.Body == nil {
+				return nil
+			}
+			return &Function{Type: fn.Type, Body: fn.Body}
+		case *ast.FuncLit:
+			return &Function{Type: fn.Type, Body: fn.Body}
+		}
+	}
+	return nil
+}
+
+// FileOf returns file in inspector stack.
+func FileOf(stack []ast.Node) *ast.File {
+	for _, node := range stack {
+		if file, ok := node.(*ast.File); ok {
+			return file
+		}
+	}
+	return nil
+}
+
+// IsErrorType reports whether t is error type.
+func IsErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// ErrfObject returns name of errflow package-level object or method, which ident refers to,
+// or empty string otherwise.
+func ErrfObject(info *types.Info, ident *ast.Ident) string {
+	obj := info.Uses[ident]
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != ErrfPath {
+		return ""
+	}
+	return obj.Name()
+}
+
+// IfErrorScope returns 'defer errf.IfError()...' statement in function body,
+// which precedes pos, or nil, if there is no such statement.
+// Errors, which are sent before the statement, are not processed by IfError() handler.
+func IfErrorScope(info *types.Info, fn *Function, pos token.Pos) *ast.DeferStmt {
+	for _, stmt := range fn.Body.List {
+		if stmt.Pos() >= pos {
+			return nil
+		}
+		deferStmt, ok := stmt.(*ast.DeferStmt)
+		if !ok {
+			continue
+		}
+		found := false
+		ast.Inspect(deferStmt.Call, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok && selector.Sel.Name == "IfError" {
+				if _, ok := info.Uses[selector.Sel].(*types.Func); ok && ErrfObject(info, selector.Sel) != "" {
+					found = true
+				}
+			}
+			return !found
+		})
+		if found {
+			return deferStmt
+		}
+	}
+	return nil
+}
+
+// AssignsTo reports whether IfError() scope statement ends with ThenAssignTo(&v).
+func AssignsTo(info *types.Info, scope *ast.DeferStmt, v types.Object) bool {
+	selector, ok := scope.Call.Fun.(*ast.SelectorExpr)
+	if !ok || ErrfObject(info, selector.Sel) != "ThenAssignTo" || len(scope.Call.Args) != 1 {
+		return false
+	}
+	unary, ok := ast.Unparen(scope.Call.Args[0]).(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return false
+	}
+	ident, ok := ast.Unparen(unary.X).(*ast.Ident)
+	return ok && v != nil && info.Uses[ident] == v
+}
+
+// ErrfImportName returns errflow package name in the file
+// and whether the file imports errflow.
+func ErrfImportName(file *ast.File) (string, bool) {
+	if file != nil {
+		for _, spec := range file.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == ErrfPath {
+				if spec.Name != nil {
+					return spec.Name.Name, true
+				}
+				return "errf", true
+			}
+		}
+	}
+	return "errf", false
+}
+
+// Render formats node as Go source.
+func Render(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
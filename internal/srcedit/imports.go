@@ -0,0 +1,125 @@
+package srcedit
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// AddImport adds import of path to src, if it is not imported yet.
+// Standard library packages are added to the group with other standard library imports,
+// other packages are added as a separate group.
+func AddImport(src []byte, path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range file.Imports {
+		if specPath, _ := strconv.Unquote(spec.Path.Value); specPath == path {
+			return src, nil
+		}
+	}
+
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	importLine := strconv.Quote(path)
+	std := isStdPath(path)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.IMPORT {
+			continue
+		}
+		if !genDecl.Rparen.IsValid() {
+			spec := string(src[offset(genDecl.Specs[0].Pos()):offset(genDecl.Specs[0].End())])
+			lines := spec + "\n\n" + importLine
+			if isStdPath(file.Imports[0].Path.Value) == std {
+				lines = spec + "\n" + importLine
+			} else if std {
+				lines = importLine + "\n\n" + spec
+			}
+			return Apply(src, []Edit{{
+				Start: offset(genDecl.Pos()),
+				End:   offset(genDecl.End()),
+				Text:  "import (\n" + lines + "\n)",
+			}})
+		}
+
+		var lastSameGroup ast.Spec
+		for _, spec := range genDecl.Specs {
+			specPath, _ := strconv.Unquote(spec.(*ast.ImportSpec).Path.Value)
+			if isStdPath(specPath) == std {
+				lastSameGroup = spec
+			}
+		}
+		switch {
+		case lastSameGroup != nil:
+			end := offset(lastSameGroup.End())
+			return Apply(src, []Edit{{Start: end, End: end, Text: "\n" + importLine}})
+		case std && len(genDecl.Specs) > 0:
+			start := offset(genDecl.Specs[0].Pos())
+			return Apply(src, []Edit{{Start: start, End: start, Text: importLine + "\n\n"}})
+		default:
+			end := offset(genDecl.Rparen)
+			return Apply(src, []Edit{{Start: end, End: end, Text: "\n" + importLine + "\n"}})
+		}
+	}
+
+	end := offset(file.Name.End())
+	return Apply(src, []Edit{{Start: end, End: end, Text: "\n\nimport " + importLine + "\n"}})
+}
+
+// RemoveUnusedImport removes import of path from src, if package is not referenced anymore.
+// Package is considered to be referenced if there is a selector expression with its name.
+func RemoveUnusedImport(src []byte, path string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range file.Imports {
+		specPath, _ := strconv.Unquote(spec.Path.Value)
+		if specPath != path || (spec.Name != nil && (spec.Name.Name == "_" || spec.Name.Name == ".")) {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		used := false
+		ast.Inspect(file, func(node ast.Node) bool {
+			if selector, ok := node.(*ast.SelectorExpr); ok {
+				if ident, ok := selector.X.(*ast.Ident); ok && ident.Name == name {
+					used = true
+				}
+			}
+			return !used
+		})
+		if used {
+			return src, nil
+		}
+
+		start := fset.Position(spec.Pos()).Offset
+		end := fset.Position(spec.End()).Offset
+		for _, decl := range file.Decls {
+			if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT &&
+				len(genDecl.Specs) == 1 && genDecl.Specs[0] == ast.Spec(spec) {
+				start = fset.Position(genDecl.Pos()).Offset
+				end = fset.Position(genDecl.End()).Offset
+			}
+		}
+		return Apply(src, []Edit{{Start: start, End: end}})
+	}
+	return src, nil
+}
+
+// isStdPath reports whether import path belongs to standard library.
+func isStdPath(path string) bool {
+	path = strings.Trim(path, `"`)
+	return !strings.Contains(strings.Split(path, "/")[0], ".")
+}
@@ -0,0 +1,65 @@
+package srcedit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AddImport(t *testing.T) {
+	for _, tc := range []struct {
+		src      string
+		path     string
+		expected string
+	}{
+		{
+			src:      "package p\n",
+			path:     "fmt",
+			expected: "package p\n\nimport \"fmt\"\n",
+		},
+		{
+			src:      "package p\n\nimport \"os\"\n",
+			path:     "fmt",
+			expected: "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n",
+		},
+		{
+			src:      "package p\n\nimport \"os\"\n",
+			path:     "example.com/x",
+			expected: "package p\n\nimport (\n\t\"os\"\n\n\t\"example.com/x\"\n)\n",
+		},
+		{
+			src:      "package p\n\nimport (\n\t\"os\"\n\n\t\"example.com/x\"\n)\n",
+			path:     "fmt",
+			expected: "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\n\t\"example.com/x\"\n)\n",
+		},
+		{
+			src:      "package p\n\nimport (\n\t\"example.com/x\"\n)\n",
+			path:     "fmt",
+			expected: "package p\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/x\"\n)\n",
+		},
+		{
+			src:      "package p\n\nimport (\n\t\"fmt\"\n)\n",
+			path:     "fmt",
+			expected: "package p\n\nimport (\n\t\"fmt\"\n)\n",
+		},
+	} {
+		result, err := AddImport([]byte(tc.src), tc.path)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, string(result))
+	}
+}
+
+func Test_RemoveUnusedImport(t *testing.T) {
+	src := "package p\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nvar _ = os.Args\n"
+	result, err := RemoveUnusedImport([]byte(src), "fmt")
+	assert.NoError(t, err)
+	assert.Equal(t, "package p\n\nimport (\n\t\"os\"\n)\n\nvar _ = os.Args\n", string(result))
+
+	result, err = RemoveUnusedImport([]byte(src), "os")
+	assert.NoError(t, err)
+	assert.Equal(t, src, string(result))
+
+	result, err = RemoveUnusedImport([]byte("package p\n\nimport \"fmt\"\n"), "fmt")
+	assert.NoError(t, err)
+	assert.Equal(t, "package p\n", string(result))
+}
@@ -0,0 +1,153 @@
+// Package srcedit implements source code edits and diffs for errflow command line tools.
+package srcedit
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// Edit replaces source bytes in [Start, End) range with Text.
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Apply applies edits to src and formats result using go/format.
+// Edits should not overlap.
+func Apply(src []byte, edits []Edit) ([]byte, error) {
+	sorted := append([]Edit{}, edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	var result strings.Builder
+	offset := 0
+	for _, edit := range sorted {
+		if edit.Start < offset || edit.End < edit.Start || edit.End > len(src) {
+			return nil, fmt.Errorf("invalid or overlapping edit [%d, %d)", edit.Start, edit.End)
+		}
+		result.Write(src[offset:edit.Start])
+		result.WriteString(edit.Text)
+		offset = edit.End
+	}
+	result.Write(src[offset:])
+
+	formatted, err := format.Source([]byte(result.String()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot format edited source: %w", err)
+	}
+	return formatted, nil
+}
+
+// Diff returns unified diff between oldSrc and newSrc.
+// Returns empty string if there are no differences.
+func Diff(filename string, oldSrc, newSrc []byte) string {
+	oldLines := splitLines(string(oldSrc))
+	newLines := splitLines(string(newSrc))
+	ops := diffLines(oldLines, newLines)
+
+	const context = 3
+	var result strings.Builder
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		hunkStart := start - context
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := start
+		for unchanged := 0; hunkEnd < len(ops) && unchanged <= 2*context; hunkEnd++ {
+			if ops[hunkEnd].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		for hunkEnd > start && ops[hunkEnd-1].kind == ' ' && trailingContext(ops[:hunkEnd]) > context {
+			hunkEnd--
+		}
+
+		if result.Len() == 0 {
+			fmt.Fprintf(&result, "--- %s\n+++ %s\n", filename, filename)
+		}
+		oldStart, newStart, oldCount, newCount := ops[hunkStart].oldLine, ops[hunkStart].newLine, 0, 0
+		var body strings.Builder
+		for _, op := range ops[hunkStart:hunkEnd] {
+			if op.kind != '+' {
+				oldCount++
+			}
+			if op.kind != '-' {
+				newCount++
+			}
+			fmt.Fprintf(&body, "%c%s\n", op.kind, op.line)
+		}
+		fmt.Fprintf(&result, "@@ -%d,%d +%d,%d @@\n%s", oldStart+1, oldCount, newStart+1, newCount, body.String())
+		start = hunkEnd
+	}
+	return result.String()
+}
+
+type diffOp struct {
+	kind    byte
+	line    string
+	oldLine int
+	newLine int
+}
+
+func trailingContext(ops []diffOp) int {
+	count := 0
+	for i := len(ops) - 1; i >= 0 && ops[i].kind == ' '; i-- {
+		count++
+	}
+	return count
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines computes line diff using longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i], oldLine: i, newLine: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: a[i], oldLine: i, newLine: j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j], oldLine: i, newLine: j})
+			j++
+		}
+	}
+	return ops
+}
@@ -0,0 +1,34 @@
+package srcedit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Apply(t *testing.T) {
+	src := []byte("package p\n\nfunc f() {\n\tx := 1\n\t_ = x\n}\n")
+	x1 := strings.Index(string(src), "x := 1")
+	x2 := strings.Index(string(src), "_ = x")
+
+	result, err := Apply(src, []Edit{
+		{Start: x2, End: x2 + len("_ = x"), Text: "_ = 2"},
+		{Start: x1, End: x1 + len("x := 1"), Text: "x:=2\n"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "package p\n\nfunc f() {\n\tx := 2\n\n\t_ = 2\n}\n", string(result))
+
+	_, err = Apply(src, []Edit{{Start: 10, End: 20}, {Start: 15, End: 25}})
+	assert.EqualError(t, err, "invalid or overlapping edit [15, 25)")
+}
+
+func Test_Diff(t *testing.T) {
+	oldSrc := []byte("a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n")
+	newSrc := []byte("a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nL\nm\n")
+
+	assert.Equal(t, "--- f.go\n+++ f.go\n"+
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n"+
+		"@@ -9,5 +9,5 @@\n i\n j\n k\n-l\n+L\n m\n", Diff("f.go", oldSrc, newSrc))
+	assert.Equal(t, "", Diff("f.go", oldSrc, oldSrc))
+}
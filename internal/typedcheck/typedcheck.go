@@ -0,0 +1,60 @@
+// Package typedcheck maps Go types to typed errflow Check* functions
+// (e.g. int -> errf.Std.CheckInt, *os.File -> errf.Os.CheckFile).
+package typedcheck
+
+import (
+	"go/types"
+)
+
+var basicNames = map[types.BasicKind]string{
+	types.Int: "Int", types.Int8: "Int8", types.Int16: "Int16", types.Int32: "Int32", types.Int64: "Int64",
+	types.Uint: "Uint", types.Uint8: "Uint8", types.Uint16: "Uint16", types.Uint32: "Uint32", types.Uint64: "Uint64",
+	types.Uintptr: "Uintptr", types.Float32: "Float32", types.Float64: "Float64",
+	types.Complex64: "Complex64", types.Complex128: "Complex128", types.Bool: "Bool", types.String: "String",
+}
+
+// namedTypes maps type names to table and method names.
+//
+// Net table is not included: errf.Net.Check* functions classify errors
+// (e.g. into *errf.NetTimeoutError), which changes errors returned by a function.
+var namedTypes = map[string][2]string{
+	"*os.File":                {"Os", "CheckFile"},
+	"io.Reader":               {"Io", "CheckReader"},
+	"io.Writer":               {"Io", "CheckWriter"},
+	"io.ReadCloser":           {"Io", "CheckReadCloser"},
+	"io.WriteCloser":          {"Io", "CheckWriteCloser"},
+	"*bufio.Reader":           {"Bufio", "CheckReader"},
+	"*bufio.Writer":           {"Bufio", "CheckWriter"},
+	"*bufio.ReadWriter":       {"Bufio", "CheckReadWriter"},
+	"*compress/gzip.Reader":   {"Compress", "CheckGzipReader"},
+	"*compress/gzip.Writer":   {"Compress", "CheckGzipWriter"},
+	"*compress/zlib.Writer":   {"Compress", "CheckZlibWriter"},
+	"*compress/flate.Writer":  {"Compress", "CheckFlateWriter"},
+	"*archive/zip.Reader":     {"Archive", "CheckZipReader"},
+	"*archive/zip.ReadCloser": {"Archive", "CheckZipReadCloser"},
+}
+
+// Lookup returns typed Check* table and method for t (e.g. "Std", "CheckInt").
+// It returns empty strings, if there is no typed Check* function for t.
+func Lookup(t types.Type) (table string, method string) {
+	t = types.Unalias(t)
+	if basic, ok := t.(*types.Basic); ok {
+		if name, ok := basicNames[basic.Kind()]; ok {
+			return "Std", "Check" + name
+		}
+	}
+	if slice, ok := t.(*types.Slice); ok {
+		if basic, ok := types.Unalias(slice.Elem()).(*types.Basic); ok {
+			if basic.Kind() == types.Uint8 {
+				return "Std", "CheckByteSlice"
+			}
+			if name, ok := basicNames[basic.Kind()]; ok {
+				return "Std", "Check" + name + "Slice"
+			}
+		}
+	}
+	if names, ok := namedTypes[types.TypeString(t, nil)]; ok {
+		return names[0], names[1]
+	}
+	return "", ""
+}
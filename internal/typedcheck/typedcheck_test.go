@@ -0,0 +1,41 @@
+package typedcheck
+
+import (
+	"go/importer"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func lookupType(t *testing.T, path, name string) types.Type {
+	pkg, err := importer.ForCompiler(token.NewFileSet(), "source", nil).Import(path)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	return pkg.Scope().Lookup(name).Type()
+}
+
+func Test_Lookup(t *testing.T) {
+	for _, tc := range []struct {
+		t      types.Type
+		table  string
+		method string
+	}{
+		{t: types.Typ[types.Int], table: "Std", method: "CheckInt"},
+		{t: types.Typ[types.String], table: "Std", method: "CheckString"},
+		{t: types.NewSlice(types.Typ[types.Byte]), table: "Std", method: "CheckByteSlice"},
+		{t: types.NewSlice(types.Typ[types.Float64]), table: "Std", method: "CheckFloat64Slice"},
+		{t: types.NewPointer(lookupType(t, "os", "File")), table: "Os", method: "CheckFile"},
+		{t: lookupType(t, "io", "WriteCloser"), table: "Io", method: "CheckWriteCloser"},
+		{t: types.NewPointer(lookupType(t, "compress/gzip", "Reader")), table: "Compress", method: "CheckGzipReader"},
+		{t: lookupType(t, "os", "File")},
+		{t: lookupType(t, "net", "Conn")},
+		{t: types.NewSlice(types.NewSlice(types.Typ[types.Int]))},
+	} {
+		table, method := Lookup(tc.t)
+		assert.Equal(t, tc.table, table, tc.t.String())
+		assert.Equal(t, tc.method, method, tc.t.String())
+	}
+}
@@ -1,6 +1,9 @@
 package errf
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 // Io contains collection of Check* functions for io.* types.
 var Io = IoErrflow{}
@@ -64,3 +67,31 @@ func (ef IoErrflow) CheckReadCloserErr(value io.ReadCloser, err error) (io.ReadC
 	ef.errflow.ImplementCheck(recover(), err)
 	return value, err
 }
+
+// Layered flushes and closes stacked layers (e.g. writers) in order,
+// from the outermost layer to the innermost one, and checks for errors.
+// All layers are processed, even if some of them fail.
+// Errors are combined according to return strategy.
+//
+// Each layer should implement either Close() error or Flush() error.
+// If layer implements both, only Close() is called.
+//
+// Useful in defer statements:
+//  file := errf.Io.CheckWriteCloser(os.Create(filename))
+//  gzipWriter := gzip.NewWriter(file)
+//  bufWriter := bufio.NewWriter(gzipWriter)
+//  defer errf.Io.Layered(bufWriter, gzipWriter, file)
+func (ef IoErrflow) Layered(layers ...interface{}) CheckResult {
+	var errs []error
+	for _, layer := range layers {
+		switch layer := layer.(type) {
+		case io.Closer:
+			errs = append(errs, layer.Close())
+		case interface{ Flush() error }:
+			errs = append(errs, layer.Flush())
+		default:
+			errs = append(errs, fmt.Errorf("errf.Io.Layered: %T should implement either Close() error or Flush() error", layer))
+		}
+	}
+	return ef.errflow.implementCheckAll(recover(), errs)
+}
@@ -1,7 +1,11 @@
 package errf
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -142,3 +146,97 @@ func Test_Io_CheckReadCloserErr(t *testing.T) {
 
 	assert.EqualError(t, fn(), "error")
 }
+
+type testLayer struct {
+	name   string
+	err    error
+	events *[]string
+}
+
+type testFlushLayer testLayer
+
+func (l *testLayer) Close() error {
+	*l.events = append(*l.events, "close "+l.name)
+	return l.err
+}
+
+func (l *testFlushLayer) Flush() error {
+	*l.events = append(*l.events, "flush "+l.name)
+	return l.err
+}
+
+func Test_Io_Layered(t *testing.T) {
+	var events []string
+	fn := func(errs ...error) (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		defer Io.Layered(
+			&testFlushLayer{name: "bufio", err: errs[0], events: &events},
+			&testLayer{name: "gzip", err: errs[1], events: &events},
+			&testLayer{name: "file", err: errs[2], events: &events},
+		)
+		return nil
+	}
+
+	assert.NoError(t, fn(nil, nil, nil))
+	assert.Equal(t, []string{"flush bufio", "close gzip", "close file"}, events)
+
+	events = nil
+	assert.EqualError(t, fn(fmt.Errorf("flush error"), nil, fmt.Errorf("close error")),
+		"combined error {flush error; close error}")
+	assert.Equal(t, []string{"flush bufio", "close gzip", "close file"}, events)
+}
+
+func Test_Io_Layered_AfterError(t *testing.T) {
+	var events []string
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer Io.Layered(&testLayer{name: "file", err: fmt.Errorf("close error"), events: &events})
+		return CheckErr(fmt.Errorf("write error")).IfOkReturnNil
+	}
+
+	assert.EqualError(t, fn(), "write error")
+	assert.Equal(t, []string{"close file"}, events)
+}
+
+func Test_Io_Layered_InvalidLayer(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer Io.Layered(123)
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "errf.Io.Layered: int should implement either Close() error or Flush() error")
+}
+
+func Test_Io_Layered_Gzip(t *testing.T) {
+	fn := func(writer io.WriteCloser) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		gzipWriter := gzip.NewWriter(writer)
+		bufWriter := bufio.NewWriter(gzipWriter)
+		defer Io.Layered(bufWriter, gzipWriter, writer)
+		return CheckDiscard(bufWriter.WriteString("hello")).IfOkReturnNil
+	}
+
+	file := &testFile{}
+	assert.NoError(t, fn(file))
+	assert.True(t, file.closed)
+	reader, err := gzip.NewReader(&file.buf)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+type testFile struct {
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (f *testFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *testFile) Close() error {
+	f.closed = true
+	return nil
+}
@@ -0,0 +1,106 @@
+package errf
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+)
+
+// Each converts a sequence of (value, error) pairs into a sequence of values.
+//
+// Values are yielded until first non-nil error is encountered.
+// This error is sent to IfError() handler for processing,
+// same as if it was checked using one of Check* functions.
+//
+// Example:
+//  func countLines(reader io.Reader) (count int, err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	for range errf.Each(errf.ScannerSeq(bufio.NewScanner(reader))) {
+//  		count++
+//  	}
+//  	return count, nil
+//  }
+func Each[T any](seq iter.Seq2[T, error]) iter.Seq[T] {
+	return each(DefaultErrflow, seq)
+}
+
+func each[T any](ef *Errflow, seq iter.Seq2[T, error]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var seqErr error
+		for value, err := range seq {
+			if err != nil {
+				seqErr = err
+				break
+			}
+			if !yield(value) {
+				return
+			}
+		}
+		ef.ImplementCheck(nil, seqErr)
+	}
+}
+
+// ScannerSeq creates a sequence of scanner tokens (scanner.Text()).
+// After the last token, scanner.Err() is yielded, if not nil.
+func ScannerSeq(scanner *bufio.Scanner) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for scanner.Scan() {
+			if !yield(scanner.Text(), nil) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// RowsSeq creates a sequence of values, produced by calling scanFn for each row.
+// After the last row, rows.Err() is yielded, if not nil.
+//
+// Clients are still responsible for closing rows, in case if iteration is stopped early.
+//
+// Example:
+//  rows := errf.CheckAny(db.Query("SELECT name FROM users")).(*sql.Rows)
+//  defer errf.CheckDeferErr(rows.Close)
+//
+//  for name := range errf.Each(errf.RowsSeq(rows, func(rows *sql.Rows) (name string, err error) {
+//  	return name, rows.Scan(&name)
+//  })) {
+//  	// ...
+//  }
+func RowsSeq[T any](rows *sql.Rows, scanFn func(rows *sql.Rows) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for rows.Next() {
+			value, err := scanFn(rows)
+			if !yield(value, err) || err != nil {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// DecoderSeq creates a sequence of JSON values, decoded from decoder stream.
+// Sequence ends when decoder reaches io.EOF.
+func DecoderSeq[T any](decoder *json.Decoder) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var value T
+			err := decoder.Decode(&value)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(value, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package errf
+
+import (
+	"bufio"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func seqOf(values []int, err error) func(yield func(int, error) bool) {
+	return func(yield func(int, error) bool) {
+		for _, value := range values {
+			if !yield(value, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(0, err)
+		}
+	}
+}
+
+func Test_Each(t *testing.T) {
+	fn := func(seqErr error) (values []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		for value := range Each(seqOf([]int{1, 2, 3}, seqErr)) {
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	values, err := fn(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, values)
+
+	values, err = fn(fmt.Errorf("error"))
+	assert.EqualError(t, err, "error")
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func Test_Each_Break(t *testing.T) {
+	fn := func() (values []int, err error) {
+		defer IfError().ThenAssignTo(&err)
+		for value := range Each(seqOf([]int{1, 2, 3}, fmt.Errorf("error"))) {
+			if value == 2 {
+				break
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	values, err := fn()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, values)
+}
+
+func Test_Each_CheckInBody(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		for value := range Each(seqOf([]int{1, 2, 3}, nil)) {
+			CheckCondition(value == 2, "error %d", value)
+		}
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "error 2")
+}
+
+type errReader struct{}
+
+func (errReader) Read(_ []byte) (int, error) {
+	return 0, fmt.Errorf("read error")
+}
+
+func Test_ScannerSeq(t *testing.T) {
+	fn := func(reader io.Reader) (lines []string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		for line := range Each(ScannerSeq(bufio.NewScanner(reader))) {
+			lines = append(lines, line)
+		}
+		return lines, nil
+	}
+
+	lines, err := fn(strings.NewReader("a\nb\nc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, lines)
+
+	_, err = fn(io.MultiReader(strings.NewReader("a\n"), errReader{}))
+	assert.EqualError(t, err, "read error")
+}
+
+type testJSONValue struct {
+	Name string `json:"name"`
+}
+
+func Test_DecoderSeq(t *testing.T) {
+	fn := func(input string) (names []string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		decoder := json.NewDecoder(strings.NewReader(input))
+		for value := range Each(DecoderSeq[testJSONValue](decoder)) {
+			names = append(names, value.Name)
+		}
+		return names, nil
+	}
+
+	names, err := fn(`{"name": "a"} {"name": "b"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	_, err = fn(`{"name": "a"} {"name": `)
+	assert.EqualError(t, err, "unexpected EOF")
+}
+
+type testSQLDriver struct{}
+
+func (testSQLDriver) Open(name string) (driver.Conn, error) {
+	return testSQLConn{rows: strings.Split(name, ",")}, nil
+}
+
+type testSQLConn struct {
+	rows []string
+}
+
+func (c testSQLConn) Prepare(_ string) (driver.Stmt, error) { return testSQLStmt(c), nil }
+func (c testSQLConn) Close() error                          { return nil }
+func (c testSQLConn) Begin() (driver.Tx, error)             { return nil, fmt.Errorf("not supported") }
+
+type testSQLStmt testSQLConn
+
+func (s testSQLStmt) Close() error                                 { return nil }
+func (s testSQLStmt) NumInput() int                                { return 0 }
+func (s testSQLStmt) Exec(_ []driver.Value) (driver.Result, error) { return nil, fmt.Errorf("not supported") }
+func (s testSQLStmt) Query(_ []driver.Value) (driver.Rows, error) {
+	return &testSQLRows{rows: s.rows}, nil
+}
+
+type testSQLRows struct {
+	rows []string
+}
+
+func (r *testSQLRows) Columns() []string { return []string{"value"} }
+func (r *testSQLRows) Close() error      { return nil }
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	if row == "error" {
+		return fmt.Errorf("rows error")
+	}
+	dest[0] = row
+	return nil
+}
+
+func init() {
+	sql.Register("errf-test", testSQLDriver{})
+}
+
+func Test_RowsSeq(t *testing.T) {
+	fn := func(dataSource string) (values []string, err error) {
+		defer IfError().ThenAssignTo(&err)
+		db := CheckAny(sql.Open("errf-test", dataSource)).(*sql.DB)
+		defer CheckDeferErr(db.Close)
+		rows := CheckAny(db.Query("SELECT value")).(*sql.Rows)
+		defer CheckDeferErr(rows.Close)
+
+		for value := range Each(RowsSeq(rows, func(rows *sql.Rows) (value string, err error) {
+			return value, rows.Scan(&value)
+		})) {
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	values, err := fn("a,b")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	_, err = fn("a,error")
+	assert.EqualError(t, err, "rows error")
+}
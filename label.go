@@ -0,0 +1,93 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Label creates ErrflowOption, which tags errors from Check* functions with a label.
+//
+// Labels allow to distinguish between multiple Check* calls in a single function
+// without inspecting error values.
+//
+// Same as with strategies, first applied label takes precedence,
+// so labels set on Check* functions are not overridden by IfError().Apply(errf.Label(...)),
+// unless errf.Override is used.
+//
+// See also: Handle().OnLabel(...), errf.LabelOf(...).
+//
+// Example:
+//  func copyFile(src, dst string) (err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	reader := errf.Io.CheckReadCloser(os.Open(src))
+//  	defer errf.CheckDeferErr(reader.Close)
+//
+//  	writer := errf.Io.With(errf.Label("open-dst")).CheckWriteCloser(os.Create(dst))
+//  	defer errf.Handle().OnLabel("open-dst", func(err error) { /* ... */ })
+//  	// ...
+//  }
+func Label(label string) ErrflowOption {
+	return func(ef *Errflow) *Errflow {
+		newEf := ef.copy()
+		if ef.label == "" {
+			newEf.label = label
+		} else if validatorEnabled && ef.label != label {
+			globalErrflowValidator.optionConflict(
+				fmt.Sprintf("Label(%q)", label), fmt.Sprintf("Label(%q)", ef.label))
+		}
+		return newEf
+	}
+}
+
+// LabeledError is an error from Check* function, configured with errf.Label(...) option.
+// Error message is not modified.
+type LabeledError struct {
+	Label string
+	Err   error
+}
+
+func (e *LabeledError) Error() string {
+	return e.Err.Error()
+}
+
+// Format implements fmt.Formatter.
+func (e *LabeledError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*LabeledError) errfFormatted() {}
+
+// Unwrap returns original error.
+func (e *LabeledError) Unwrap() error {
+	return e.Err
+}
+
+// LabelOf returns a label of the Check* function, which produced err.
+// Returns empty string if err is not labeled.
+//
+// Example:
+//  defer errf.IfError().Then(func(err error) {
+//  	log.Printf("%s failed: %v", errf.LabelOf(err), err)
+//  })
+func LabelOf(err error) string {
+	var labeledErr *LabeledError
+	if errors.As(err, &labeledErr) {
+		return labeledErr.Label
+	}
+	return ""
+}
+
+func (ef *Errflow) applyLabel(err error) error {
+	if ef.label == "" || err == nil {
+		return err
+	}
+	return &LabeledError{Label: ef.label, Err: err}
+}
+
+func appendLabelTag(tags []string, err error) []string {
+	if label := LabelOf(err); label != "" {
+		return append(tags, "label:"+label)
+	}
+	return tags
+}
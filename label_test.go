@@ -0,0 +1,97 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Label(t *testing.T) {
+	var labels []string
+	fn := func(err1, err2 error) (err error) {
+		defer IfError().Then(func(err error) {
+			labels = append(labels, LabelOf(err))
+		})
+
+		With(Label("first")).CheckErr(err1)
+		With(Label("second")).CheckErr(err2)
+		return nil
+	}
+
+	fn(fmt.Errorf("error 1"), nil)
+	fn(nil, fmt.Errorf("error 2"))
+	fn(nil, nil)
+	assert.Equal(t, []string{"first", "second"}, labels)
+}
+
+func Test_Label_ErrorIsNotModified(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		With(Label("label"), WrapperFmtErrorw("wrapped")).CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "wrapped: test error")
+	assert.True(t, errors.Is(err, errTestErrorInstance))
+	assert.Equal(t, "label", LabelOf(err))
+}
+
+func Test_LabelOf_NotLabeled(t *testing.T) {
+	assert.Equal(t, "", LabelOf(nil))
+	assert.Equal(t, "", LabelOf(fmt.Errorf("error")))
+}
+
+func Test_Handle_OnLabel(t *testing.T) {
+	var calls []string
+	fn := func(err1, err2 error) (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		defer Handle().OnLabel("first", func(err error) {
+			calls = append(calls, "first: "+err.Error())
+		})
+		With(Label("first")).CheckErr(err1)
+
+		defer Handle().OnLabel("second", func(err error) {
+			calls = append(calls, "second: "+err.Error())
+		})
+		With(Label("second")).CheckErr(err2)
+		return nil
+	}
+
+	assert.EqualError(t, fn(fmt.Errorf("error 1"), nil), "error 1")
+	assert.EqualError(t, fn(nil, fmt.Errorf("error 2")), "error 2")
+	assert.NoError(t, fn(nil, nil))
+	assert.Equal(t, []string{"first: error 1", "second: error 2"}, calls)
+}
+
+func Test_Label_LogTags(t *testing.T) {
+	var tags [][]string
+	defer SetLogFn(func(logMessage *LogMessage) {
+		tags = append(tags, logMessage.Tags)
+	}).ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		With(Label("label")).CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "test error")
+	assert.Equal(t, [][]string{{"errorflow", "error", "label:label"}}, tags)
+}
+
+func Test_Label_Precedence(t *testing.T) {
+	fn := func(err1, err2 error) (err error) {
+		defer IfError().Apply(Label("outer")).ThenAssignTo(&err)
+
+		With(Label("inner")).CheckErr(err1)
+		CheckErr(err2)
+		return nil
+	}
+
+	assert.Equal(t, "inner", LabelOf(fn(errTestErrorInstance, nil)))
+	assert.Equal(t, "outer", LabelOf(fn(nil, errTestErrorInstance)))
+}
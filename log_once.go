@@ -0,0 +1,76 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// loggedError marks errors, which were already logged by errflow.
+// Error message is not modified.
+type loggedError struct {
+	err error
+}
+
+func (e *loggedError) Error() string {
+	return e.err.Error()
+}
+
+// Format implements fmt.Formatter.
+func (e *loggedError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*loggedError) errfFormatted() {}
+
+func (e *loggedError) Unwrap() error {
+	return e.err
+}
+
+func markLogged(err error) error {
+	if _, ok := err.(*loggedError); ok || err == nil {
+		return err
+	}
+	return &loggedError{err: err}
+}
+
+// IsLogged returns true if err was already logged by errflow.
+//
+// errflow marks errors it logs, and doesn't log marked errors again
+// (unless LogForce option is used). That way, error is logged only once,
+// even if multiple functions up the call stack use LogStrategyAlways.
+//
+// Combined errors (see ReturnStrategyCombined) are considered logged
+// when all errors they contain are logged.
+func IsLogged(err error) bool {
+	if err == nil {
+		return false
+	}
+	var logged *loggedError
+	if errors.As(err, &logged) {
+		return true
+	}
+	errs := GetCombinedErrors(err)
+	if len(errs) < 2 {
+		return false
+	}
+	for _, e := range errs {
+		if !IsLogged(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// LogForce configures Errflow instance to log errors even if they were already logged.
+//
+// Example:
+//  defer errf.IfError().LogAlways().LogForce().ThenAssignTo(&err)
+func LogForce(ef *Errflow) *Errflow {
+	newEf := ef.copy()
+	newEf.logForce = true
+	return newEf
+}
+
+func (ef *Errflow) shouldLog(err error) bool {
+	return ef.logForce || !IsLogged(err)
+}
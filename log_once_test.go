@@ -0,0 +1,120 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectLogs(logs *[]string) DeferRestorer {
+	return SetLogFn(func(logMessage *LogMessage) {
+		*logs = append(*logs, fmt.Sprintf(logMessage.Format, logMessage.A...))
+	})
+}
+
+func Test_LogOnce_NestedLogAlways(t *testing.T) {
+	var logs []string
+	defer collectLogs(&logs).ThenRestore()
+
+	inner := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		CheckErr(inner())
+		return nil
+	}
+
+	err := outer()
+	assert.EqualError(t, err, "test error")
+	assert.True(t, errors.Is(err, errTestErrorInstance))
+	assert.True(t, IsLogged(err))
+	assert.Equal(t, []string{"test error"}, logs)
+
+	Log(err)
+	LogDefer(func() error { return err })
+	assert.Equal(t, []string{"test error"}, logs)
+}
+
+func Test_LogOnce_LogForce(t *testing.T) {
+	var logs []string
+	defer collectLogs(&logs).ThenRestore()
+
+	inner := func() (err error) {
+		defer IfError().LogAlways().ThenAssignTo(&err)
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().LogAlways().LogForce().ThenAssignTo(&err)
+		CheckErr(inner())
+		return nil
+	}
+
+	err := outer()
+	assert.Equal(t, []string{"test error", "test error"}, logs)
+
+	With(LogForce).Log(err)
+	assert.Equal(t, []string{"test error", "test error", "test error"}, logs)
+}
+
+func Test_LogOnce_IfErrorAssignTo(t *testing.T) {
+	var logs []string
+	defer collectLogs(&logs).ThenRestore()
+
+	fn := func() (err error) {
+		defer With(LogStrategyAlways).IfErrorAssignTo(&err, errorFn("close error"))
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "close error")
+	assert.True(t, IsLogged(err))
+
+	fn2 := func() (err error) {
+		defer With(LogStrategyAlways).IfErrorAssignTo(&err, func() error { return err })
+		return fn()
+	}
+	logs = nil
+	assert.EqualError(t, fn2(), "close error")
+	assert.Equal(t, []string{"close error"}, logs)
+}
+
+func Test_LogOnce_SuppressedErrors(t *testing.T) {
+	var logs []string
+	defer collectLogs(&logs).ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().LogIfSuppressed().AttachSuppressed().ThenAssignTo(&err)
+		defer CheckDeferErr(errorFn("close error"))
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.False(t, IsLogged(err))
+	assert.Equal(t, 1, len(Suppressed(err)))
+	assert.True(t, IsLogged(Suppressed(err)[0]))
+	assert.Equal(t, []string{"close error"}, logs)
+
+	Log(Suppressed(err)[0])
+	Log(err)
+	assert.Equal(t, []string{"close error", "test error"}, logs)
+}
+
+func Test_IsLogged_Combined(t *testing.T) {
+	logged1 := markLogged(fmt.Errorf("error 1"))
+	logged2 := markLogged(fmt.Errorf("error 2"))
+	notLogged := fmt.Errorf("error 3")
+
+	assert.False(t, IsLogged(nil))
+	assert.False(t, IsLogged(notLogged))
+	assert.True(t, IsLogged(logged1))
+	assert.True(t, IsLogged(fmt.Errorf("wrapped: %w", logged1)))
+	assert.True(t, IsLogged(CombinedError{errs: []error{logged1, logged2}}))
+	assert.False(t, IsLogged(CombinedError{errs: []error{logged1, notLogged}}))
+}
@@ -1,5 +1,7 @@
 package errf
 
+import "fmt"
+
 type logStrategy int
 
 const (
@@ -9,14 +11,30 @@ const (
 	logStrategyAlways
 )
 
+// setLogStrategy sets log strategy, unless it is already set:
+// first applied log strategy takes precedence (see Override).
 func setLogStrategy(ef *Errflow, ls logStrategy) *Errflow {
 	newEf := ef.copy()
 	if ef.logStrategy == logStrategyDefault {
 		newEf.logStrategy = ls
+	} else if validatorEnabled && ef.logStrategy != ls {
+		globalErrflowValidator.optionConflict(logStrategyName(ls), logStrategyName(ef.logStrategy))
 	}
 	return newEf
 }
 
+func logStrategyName(ls logStrategy) string {
+	switch ls {
+	case logStrategyDefault, logStrategyNever:
+		return "LogStrategyNever"
+	case logStrategyIfSuppressed:
+		return "LogStrategyIfSuppressed"
+	case logStrategyAlways:
+		return "LogStrategyAlways"
+	}
+	return fmt.Sprintf("logStrategy(%d)", ls)
+}
+
 // LogStrategyNever configures Errflow instance to never log errors.
 // This is default behavior.
 func LogStrategyNever(ef *Errflow) *Errflow {
@@ -0,0 +1,164 @@
+package errf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Net contains collection of Check* functions for net.* types.
+//
+// Errors checked by Net functions are classified using net.Error interface:
+// timeouts are wrapped in *NetTimeoutError and temporary errors
+// are wrapped in *NetTemporaryError. It allows handling them separately:
+//  defer errf.Handle().OnErrAs(func(err *errf.NetTimeoutError) {
+//  	// ...
+//  })
+var Net = NetErrflow{}
+
+// NetErrflow implements Check* functions for net package types.
+//
+// Clients should not instantiate NetErrflow, use 'errf.Net' instead.
+type NetErrflow struct {
+	errflow *Errflow
+}
+
+// NetTimeoutError wraps net.Error errors, which indicate timeout.
+type NetTimeoutError struct {
+	Err error
+}
+
+func (e *NetTimeoutError) Error() string {
+	return e.Err.Error()
+}
+
+// Format implements fmt.Formatter.
+func (e *NetTimeoutError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*NetTimeoutError) errfFormatted() {}
+
+// Unwrap returns original error.
+func (e *NetTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// NetTemporaryError wraps net.Error errors, which indicate temporary failure.
+type NetTemporaryError struct {
+	Err error
+}
+
+func (e *NetTemporaryError) Error() string {
+	return e.Err.Error()
+}
+
+// Format implements fmt.Formatter.
+func (e *NetTemporaryError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*NetTemporaryError) errfFormatted() {}
+
+// Unwrap returns original error.
+func (e *NetTemporaryError) Unwrap() error {
+	return e.Err
+}
+
+func classifyNetErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &NetTimeoutError{Err: err}
+	}
+	var temporaryErr interface{ Temporary() bool }
+	if errors.As(err, &temporaryErr) && temporaryErr.Temporary() {
+		return &NetTemporaryError{Err: err}
+	}
+	return err
+}
+
+// With implements Errflow.With(...) for net types.
+func (ef NetErrflow) With(options ...ErrflowOption) NetErrflow {
+	return NetErrflow{errflow: ef.errflow.With(options...)}
+}
+
+// CheckConn calls errf.Check and returns a typed value from a function call.
+func (ef NetErrflow) CheckConn(value net.Conn, err error) net.Conn {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value
+}
+
+// CheckListener calls errf.Check and returns a typed value from a function call.
+func (ef NetErrflow) CheckListener(value net.Listener, err error) net.Listener {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value
+}
+
+// CheckPacketConn calls errf.Check and returns a typed value from a function call.
+func (ef NetErrflow) CheckPacketConn(value net.PacketConn, err error) net.PacketConn {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value
+}
+
+// CheckIO calls errf.Check and returns a number of bytes from Read or Write function call.
+//
+// Example:
+//  n := errf.Net.CheckIO(conn.Read(buf))
+func (ef NetErrflow) CheckIO(n int, err error) int {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return n
+}
+
+// CheckConnErr calls errf.Check and returns a typed value and error from a function call.
+func (ef NetErrflow) CheckConnErr(value net.Conn, err error) (net.Conn, error) {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value, err
+}
+
+// CheckListenerErr calls errf.Check and returns a typed value and error from a function call.
+func (ef NetErrflow) CheckListenerErr(value net.Listener, err error) (net.Listener, error) {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value, err
+}
+
+// CheckPacketConnErr calls errf.Check and returns a typed value and error from a function call.
+func (ef NetErrflow) CheckPacketConnErr(value net.PacketConn, err error) (net.PacketConn, error) {
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return value, err
+}
+
+// CheckDial connects to the address on the named network using net.Dialer.DialContext
+// and returns a connection.
+//
+// Example:
+//  conn := errf.Net.CheckDial(ctx, "tcp", "example.com:80")
+//  defer errf.Net.CheckDeferClose(conn, 5*time.Second)
+func (ef NetErrflow) CheckDial(ctx context.Context, network, address string) net.Conn {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	ef.errflow.ImplementCheck(recover(), classifyNetErr(err))
+	return conn
+}
+
+// CheckDeferClose sets connection deadline to now+timeout, then closes connection
+// and checks for errors.
+//
+// Deadline guarantees that pending operations (e.g. flushing data to a hung peer)
+// don't block Close() indefinitely.
+//
+// Useful in defer statements:
+//  conn := errf.Net.CheckConn(listener.Accept())
+//  defer errf.Net.CheckDeferClose(conn, 5*time.Second)
+func (ef NetErrflow) CheckDeferClose(conn interface {
+	SetDeadline(t time.Time) error
+	Close() error
+}, timeout time.Duration) CheckResult {
+	// Deadline is best-effort: Close() is called and checked regardless.
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	return ef.errflow.ImplementCheck(recover(), classifyNetErr(conn.Close()))
+}
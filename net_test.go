@@ -0,0 +1,183 @@
+package errf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Net_With(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Net.With(WrapperFmtErrorw("wrapped")).CheckConn(nil, fmt.Errorf("error"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: error")
+}
+
+func Test_Net_Loopback(t *testing.T) {
+	listenFn := func() (listener net.Listener, err error) {
+		defer IfError().ThenAssignTo(&err)
+		return Net.CheckListener(net.Listen("tcp", "127.0.0.1:0")), nil
+	}
+
+	listener, err := listenFn()
+	assert.NoError(t, err)
+
+	accepted := make(chan []byte)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			close(accepted)
+			return
+		}
+		buf := make([]byte, 5)
+		n, _ := conn.Read(buf)
+		_ = conn.Close()
+		accepted <- buf[:n]
+	}()
+
+	dialFn := func(address string) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		conn := Net.CheckDial(context.Background(), "tcp", address)
+		defer Net.CheckDeferClose(conn, time.Second)
+		Net.CheckIO(conn.Write([]byte("hello")))
+		return nil
+	}
+
+	assert.NoError(t, dialFn(listener.Addr().String()))
+	assert.Equal(t, []byte("hello"), <-accepted)
+
+	address := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+	err = dialFn(address)
+	var opErr *net.OpError
+	assert.True(t, errors.As(err, &opErr))
+	assert.Equal(t, "dial", opErr.Op)
+}
+
+func Test_Net_CheckDial_Canceled(t *testing.T) {
+	fn := func(ctx context.Context) (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Net.CheckDial(ctx, "tcp", "127.0.0.1:1")
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(fn(ctx), context.Canceled))
+}
+
+func Test_Net_Timeout(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	var timeoutErr *NetTimeoutError
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer Handle().OnErrAs(func(err *NetTimeoutError) {
+			timeoutErr = err
+		})
+		defer Net.CheckDeferClose(client, time.Second)
+
+		CheckErr(client.SetReadDeadline(time.Now().Add(time.Millisecond)))
+		Net.CheckIO(client.Read(make([]byte, 1)))
+		return nil
+	}
+
+	err := fn()
+	assert.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+	assert.NotNil(t, timeoutErr)
+	assert.Same(t, timeoutErr, err)
+}
+
+// testHungConn simulates a connection to a hung peer: Close blocks
+// until connection deadline expires (or forever, if deadline is not set).
+type testHungConn struct {
+	mu       sync.Mutex
+	deadline time.Time
+	release  chan struct{}
+}
+
+func (c *testHungConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	return nil
+}
+
+func (c *testHungConn) Close() error {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	if deadline.IsZero() {
+		<-c.release
+		return nil
+	}
+	time.Sleep(time.Until(deadline))
+	return fmt.Errorf("close: %w", os.ErrDeadlineExceeded)
+}
+
+func Test_Net_CheckDeferClose_HungPeer(t *testing.T) {
+	conn := &testHungConn{release: make(chan struct{})}
+	defer close(conn.release)
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		defer Net.CheckDeferClose(conn, time.Millisecond)
+		return nil
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		var timeoutErr *NetTimeoutError
+		assert.True(t, errors.As(err, &timeoutErr))
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("CheckDeferClose is blocked")
+	}
+}
+
+type testTemporaryErr struct{}
+
+func (testTemporaryErr) Error() string   { return "temporary error" }
+func (testTemporaryErr) Timeout() bool   { return false }
+func (testTemporaryErr) Temporary() bool { return true }
+
+func Test_Net_Temporary(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Net.CheckPacketConn(nil, testTemporaryErr{})
+		return nil
+	}
+
+	err := fn()
+	var temporaryErr *NetTemporaryError
+	assert.True(t, errors.As(err, &temporaryErr))
+	assert.EqualError(t, err, "temporary error")
+}
+
+func Test_Net_CheckErrFunctions(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().ThenAssignTo(&err)
+		defer Net.CheckConnErr(nil, fmt.Errorf("error 1"))
+		defer Net.CheckListenerErr(nil, fmt.Errorf("error 2"))
+		defer Net.CheckPacketConnErr(nil, fmt.Errorf("error 3"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "combined error {error 3; error 2; error 1}")
+}
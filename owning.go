@@ -0,0 +1,106 @@
+package errf
+
+import (
+	"fmt"
+	"io"
+)
+
+type ownedCloser struct {
+	closer    io.Closer
+	onErrOnly bool
+}
+
+var owningScopes = newGoroutineScopes[*IfErrorHandler]()
+
+// Owning configures IfErrorHandler to own resources registered using errf.Own and errf.OwnOnErr.
+//
+// Owned resources are closed in LIFO order, when handler scope ends.
+// Close errors are processed same as errors from Check* functions
+// (e.g. combined using return strategy).
+//
+// Example:
+//  func copyFile(dst, src string) (err error) {
+//  	defer errf.IfError().Owning().ThenAssignTo(&err)
+//
+//  	reader := errf.Own(os.Open(src))
+//  	writer := errf.Own(os.Create(dst))
+//
+//  	return errf.CheckDiscard(io.Copy(writer, reader)).IfOkReturnNil
+//  }
+func (c *IfErrorHandler) Owning() *IfErrorHandler {
+	if !c.owning {
+		c.owning = true
+		c.owningFn = callerFunction()
+		owningScopes.push(c)
+	}
+	return c
+}
+
+// currentOwningScope returns 'IfError().Owning()' handler of the function, which called errflow.
+func currentOwningScope() *IfErrorHandler {
+	scope, ok := owningScopes.top()
+	if !ok {
+		panic(fmt.Errorf("errf.Own requires 'defer errf.IfError().Owning()...' in the current goroutine"))
+	}
+	if fn := callerFunction(); scope.owningFn != fn {
+		panic(fmt.Errorf("errf.Own requires 'defer errf.IfError().Owning()...' in the same function,"+
+			" but it is set up in %s instead of %s", scope.owningFn, fn))
+	}
+	return scope
+}
+
+func (c *IfErrorHandler) closeOwned(failed bool) []errflowThrowItem {
+	owningScopes.remove(c)
+
+	var items []errflowThrowItem
+	for idx := len(c.owned) - 1; idx >= 0; idx-- {
+		owned := c.owned[idx]
+		if owned.onErrOnly && !failed {
+			continue
+		}
+		err := owned.closer.Close()
+		c.trace.add(TraceCheck, "", err, "owned %T.Close(): %s", owned.closer, errString(err))
+		if err != nil {
+			items = append(items, errflowThrowItem{ef: DefaultErrflow, err: err})
+		}
+	}
+	c.owned = nil
+	return items
+}
+
+// Own checks err (same as other Check* functions) and registers value to be closed,
+// when enclosing 'IfError().Owning()' handler scope ends.
+//
+// Own panics, if there is no 'IfError().Owning()' handler in the same function.
+//
+// Example:
+//  reader := errf.Own(os.Open(filename))
+//  	/* same as */
+//  reader := errf.Os.CheckFile(os.Open(filename))
+//  defer errf.CheckDeferErr(reader.Close)
+func Own[T io.Closer](value T, err error) T {
+	DefaultErrflow.ImplementCheck(recover(), err)
+	scope := currentOwningScope()
+	scope.owned = append(scope.owned, ownedCloser{closer: value})
+	return value
+}
+
+// OwnOnErr is same as Own, but value is closed only if handler scope fails
+// (with an error or a panic).
+//
+// Useful for resources, which are returned to the caller on success.
+//
+// Example:
+//  func openConfig(filename string) (file *os.File, err error) {
+//  	defer errf.IfError().Owning().ThenAssignTo(&err)
+//
+//  	file = errf.OwnOnErr(os.Open(filename))
+//  	errf.CheckErr(validateConfig(file))
+//  	return file, nil
+//  }
+func OwnOnErr[T io.Closer](value T, err error) T {
+	DefaultErrflow.ImplementCheck(recover(), err)
+	scope := currentOwningScope()
+	scope.owned = append(scope.owned, ownedCloser{closer: value, onErrOnly: true})
+	return value
+}
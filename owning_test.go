@@ -0,0 +1,141 @@
+package errf
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCloser struct {
+	name   string
+	err    error
+	events *[]string
+}
+
+func (c *testCloser) Close() error {
+	*c.events = append(*c.events, "close "+c.name)
+	return c.err
+}
+
+func Test_Own(t *testing.T) {
+	var events []string
+	fn := func(checkErr error) (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+
+		Own(&testCloser{name: "a", events: &events}, nil)
+		Own(&testCloser{name: "b", events: &events}, nil)
+		OwnOnErr(&testCloser{name: "c", events: &events}, nil)
+		return CheckErr(checkErr).IfOkReturnNil
+	}
+
+	assert.NoError(t, fn(nil))
+	assert.Equal(t, []string{"close b", "close a"}, events)
+
+	events = nil
+	assert.EqualError(t, fn(fmt.Errorf("error")), "error")
+	assert.Equal(t, []string{"close c", "close b", "close a"}, events)
+}
+
+func Test_Own_CheckError(t *testing.T) {
+	var events []string
+	fn := func() (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+
+		Own(&testCloser{name: "a", events: &events}, nil)
+		Own(&testCloser{name: "b", events: &events}, fmt.Errorf("open error"))
+		Own(&testCloser{name: "c", events: &events}, nil)
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "open error")
+	assert.Equal(t, []string{"close a"}, events)
+}
+
+func Test_Own_CloseErrors(t *testing.T) {
+	var events []string
+	fn := func(checkErr error) (err error) {
+		defer IfError().Owning().ReturnCombined().ThenAssignTo(&err)
+
+		Own(&testCloser{name: "a", err: fmt.Errorf("close a error"), events: &events}, nil)
+		Own(&testCloser{name: "b", err: fmt.Errorf("close b error"), events: &events}, nil)
+		return CheckErr(checkErr).IfOkReturnNil
+	}
+
+	assert.EqualError(t, fn(nil), "combined error {close b error; close a error}")
+	assert.EqualError(t, fn(fmt.Errorf("error")), "combined error {error; close b error; close a error}")
+}
+
+func Test_Own_Panic(t *testing.T) {
+	var events []string
+	fn := func() (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+
+		Own(&testCloser{name: "a", events: &events}, nil)
+		OwnOnErr(&testCloser{name: "b", events: &events}, nil)
+		panic("test panic")
+	}
+
+	assert.PanicsWithValue(t, "test panic", func() {
+		_ = fn()
+	})
+	assert.Equal(t, []string{"close b", "close a"}, events)
+}
+
+func Test_Own_NestedScopes(t *testing.T) {
+	var events []string
+	inner := func() (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+		Own(&testCloser{name: "inner", events: &events}, nil)
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+		Own(&testCloser{name: "outer 1", events: &events}, nil)
+		CheckErr(inner())
+		Own(&testCloser{name: "outer 2", events: &events}, nil)
+		return nil
+	}
+
+	assert.NoError(t, outer())
+	assert.Equal(t, []string{"close inner", "close outer 2", "close outer 1"}, events)
+	assert.Empty(t, owningScopes.byGoroutine)
+}
+
+func Test_Own_WithoutScope(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Own(&testCloser{}, nil)
+		return nil
+	}
+
+	assert.PanicsWithError(t,
+		"errf.Own requires 'defer errf.IfError().Owning()...' in the current goroutine",
+		func() {
+			_ = fn()
+		})
+}
+
+func Test_Own_NestedFunctionWithoutScope(t *testing.T) {
+	var events []string
+	helper := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+		Own(&testCloser{name: "helper", events: &events}, nil)
+		return nil
+	}
+	fn := func() (err error) {
+		defer IfError().Owning().ThenAssignTo(&err)
+		Own(&testCloser{name: "fn", events: &events}, nil)
+		return helper()
+	}
+
+	assert.PanicsWithError(t,
+		"errf.Own requires 'defer errf.IfError().Owning()...' in the same function, "+
+			"but it is set up in github.com/serhiy-t/errf.Test_Own_NestedFunctionWithoutScope.func2 "+
+			"instead of github.com/serhiy-t/errf.Test_Own_NestedFunctionWithoutScope.func1",
+		func() {
+			_ = fn()
+		})
+	assert.Equal(t, []string{"close fn"}, events)
+	assert.Empty(t, owningScopes.byGoroutine)
+}
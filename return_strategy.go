@@ -39,10 +39,14 @@ func getReturnStrategyImpl(rs returnStrategy) func(error, error) (supp1, supp2 b
 	return returnStrategyFirstImpl
 }
 
+// setReturnStrategy sets return strategy, unless it is already set:
+// first applied return strategy takes precedence (see Override).
 func setReturnStrategy(ef *Errflow, rs returnStrategy) *Errflow {
 	newEf := ef.copy()
 	if ef.returnStrategy == returnStrategyDefault {
 		newEf.returnStrategy = rs
+	} else if validatorEnabled && ef.returnStrategy != rs {
+		globalErrflowValidator.optionConflict(returnStrategyName(rs), returnStrategyName(ef.returnStrategy))
 	}
 	return newEf
 }
@@ -0,0 +1,64 @@
+package errf
+
+import (
+	"sync"
+)
+
+// goroutineScopes keeps per-goroutine stacks of handler scopes
+// (e.g. IfError().Owning() handlers).
+type goroutineScopes[T comparable] struct {
+	sync.Mutex
+	byGoroutine map[int][]T
+}
+
+func newGoroutineScopes[T comparable]() *goroutineScopes[T] {
+	return &goroutineScopes[T]{byGoroutine: make(map[int][]T)}
+}
+
+// push adds scope to the current goroutine stack.
+func (s *goroutineScopes[T]) push(scope T) {
+	goID := goId()
+	s.Lock()
+	defer s.Unlock()
+	s.byGoroutine[goID] = append(s.byGoroutine[goID], scope)
+}
+
+// remove removes scope from the current goroutine stack.
+func (s *goroutineScopes[T]) remove(scope T) {
+	goID := goId()
+	s.Lock()
+	defer s.Unlock()
+	scopes := s.byGoroutine[goID]
+	for idx := len(scopes) - 1; idx >= 0; idx-- {
+		if scopes[idx] == scope {
+			scopes = append(scopes[:idx], scopes[idx+1:]...)
+			break
+		}
+	}
+	if len(scopes) == 0 {
+		delete(s.byGoroutine, goID)
+	} else {
+		s.byGoroutine[goID] = scopes
+	}
+}
+
+// top returns the innermost scope of the current goroutine.
+func (s *goroutineScopes[T]) top() (T, bool) {
+	var zero T
+	s.Lock()
+	empty := len(s.byGoroutine) == 0
+	s.Unlock()
+	if empty {
+		// Fast path: skip goroutine id lookup.
+		return zero, false
+	}
+
+	goID := goId()
+	s.Lock()
+	defer s.Unlock()
+	scopes := s.byGoroutine[goID]
+	if len(scopes) == 0 {
+		return zero, false
+	}
+	return scopes[len(scopes)-1], true
+}
@@ -1,7 +1,11 @@
 package errf
 
 import (
-	"runtime/debug"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -17,7 +21,9 @@ type parsedStack struct {
 
 func (ps parsedStack) String() string {
 	var lines []string
-	lines = append(lines, ps.goroutine)
+	if ps.goroutine != "" {
+		lines = append(lines, ps.goroutine)
+	}
 	for _, item := range ps.items {
 		lines = append(lines, item.fn)
 		lines = append(lines, item.src)
@@ -25,10 +31,27 @@ func (ps parsedStack) String() string {
 	return strings.Join(lines, "\n")
 }
 
+// maxStackDepth limits number of captured stack frames.
+const maxStackDepth = 64
+
+// maxCallerStackDepth limits number of stack frames captured to find errflow caller function.
+const maxCallerStackDepth = 32
+
+// callers captures program counters of the current goroutine stack.
+// Stack is formatted lazily (see parsePCs), so capture is cheap.
+func callers(skip int) []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// getStringErrorStackTraceFn captures current stack trace.
+// Stack trace is only formatted when returned function is called
+// (e.g. when LogFn reads LogMessage.Stack).
 func getStringErrorStackTraceFn() func() string {
-	debugStack := debug.Stack()
+	pcs := callers(1)
 	return func() string {
-		return parseErrorStackTrace(string(debugStack)).String()
+		return parsePCs(pcs).String()
 	}
 }
 
@@ -37,38 +60,201 @@ func parseErrorStackTrace(debugStack string) parsedStack {
 	lines := strings.Split(strings.TrimSpace(debugStack), "\n")
 	result.goroutine = lines[0]
 	lines = lines[1:]
+	for idx := 0; idx < len(lines)-1; idx += 2 {
+		result.items = append(result.items, parsedStackItem{
+			fn:  lines[idx],
+			src: lines[idx+1],
+		})
+	}
+	result.items = skipErrflowItems(result.items)
+	return result
+}
+
+// skipErrflowItems skips errflow and runtime frames in the beginning of the stack,
+// so the first item is the function, which uses errflow.
+func skipErrflowItems(items []parsedStackItem) []parsedStackItem {
 	idx := 0
-	for ; idx < len(lines)-1; idx += 2 {
-		if strings.HasPrefix(lines[idx], "runtime") ||
-			strings.HasPrefix(lines[idx], "testing") ||
-			strings.HasPrefix(lines[idx], "panic(") {
+	for ; idx < len(items); idx++ {
+		if strings.HasPrefix(items[idx].fn, "runtime") ||
+			strings.HasPrefix(items[idx].fn, "testing") ||
+			strings.HasPrefix(items[idx].fn, "panic(") {
 			continue
 		}
-		if strings.Contains(lines[idx], "/errf.(*Errflow).ImplementCheck(") {
-			idx += 2
+		if strings.Contains(items[idx].fn, "/errf.(*Errflow).ImplementCheck(") {
+			idx++
 			continue
 		}
-		if (strings.Contains(lines[idx], "/errf.") && !strings.Contains(lines[idx+1], "_test.go")) ||
-			strings.Contains(lines[idx], "SkipInErrfStackTrace(") {
+		if (strings.Contains(items[idx].fn, "/errf.") && !strings.Contains(items[idx].src, "_test.go")) ||
+			strings.Contains(items[idx].fn, "SkipInErrfStackTrace(") {
 			continue
 		}
-		if idx+4 < len(lines) && strings.Contains(lines[idx+4], "/errf.(*InterimHandler).handle(") {
+		if idx+2 < len(items) && strings.Contains(items[idx+2].fn, "/errf.(*InterimHandler).handle(") {
 			continue
 		}
 		break
 	}
-	if idx >= len(lines)-1 {
+	if idx >= len(items) {
 		idx = 0
 	}
-	for ; idx < len(lines)-1; idx += 2 {
-		result.items = append(result.items, parsedStackItem{
-			fn:  lines[idx],
-			src: lines[idx+1],
-		})
+	return items[idx:]
+}
+
+func getErrorStackTrace() parsedStack {
+	return parsePCs(callers(1))
+}
+
+// getCallerStackTrace is same as getErrorStackTrace, but only captures top of the stack.
+func getCallerStackTrace() parsedStack {
+	var pcs [maxCallerStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return parsePCs(pcs[:n])
+}
+
+// getStringErrorStackTraceFnFor is same as getStringErrorStackTraceFn,
+// but it reuses a stack trace from err chain, if there is one.
+//
+// Only current call stack is captured eagerly: err chain is inspected
+// when log function reads the stack trace.
+func getStringErrorStackTraceFnFor(err error) func() string {
+	callStackFn := getStringErrorStackTraceFn()
+	return func() string {
+		if pcs := findErrorStack(err); pcs != nil {
+			result := parsePCs(pcs)
+			result.goroutine = "error stack trace:"
+			return result.String()
+		}
+		if stack := findFormattedErrorStack(err); stack != "" {
+			return stack
+		}
+		return callStackFn()
+	}
+}
+
+// parsePCs converts program counters into parsedStack
+// using same rules for skipping errflow frames as parseErrorStackTrace.
+func parsePCs(pcs []uintptr) parsedStack {
+	result := parsedStack{goroutine: "stack trace:"}
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			result.items = append(result.items, parsedStackItem{
+				fn:  frame.Function + "(...)",
+				src: "\t" + frame.File + ":" + strconv.Itoa(frame.Line),
+			})
+		}
+		if !more {
+			break
+		}
 	}
+	result.items = skipErrflowItems(result.items)
 	return result
 }
 
-func getErrorStackTrace() parsedStack {
-	return parseErrorStackTrace(string(debug.Stack()))
+const maxErrorChainLength = 100
+
+// nextInErrorChain supports both Unwrap() and Cause() (e.g. github.com/pkg/errors) error chains.
+func nextInErrorChain(err error) error {
+	if next := errors.Unwrap(err); next != nil {
+		return next
+	}
+	if causer, ok := err.(interface{ Cause() error }); ok {
+		return causer.Cause()
+	}
+	return nil
+}
+
+// findErrorStack returns the deepest stack trace in err chain.
+//
+// Stack traces are detected by duck typing, without depending on libraries:
+//  * StackTrace() method returning slice of uintptr-based values (e.g. github.com/pkg/errors);
+//  * Callers() method returning slice of uintptr-based values (e.g. github.com/go-errors/errors).
+func findErrorStack(err error) []uintptr {
+	var result []uintptr
+	for i := 0; err != nil && i < maxErrorChainLength; i++ {
+		if pcs := callStackMethod(err, "StackTrace"); pcs != nil {
+			result = pcs
+		} else if pcs := callStackMethod(err, "Callers"); pcs != nil {
+			result = pcs
+		}
+		err = nextInErrorChain(err)
+	}
+	return result
+}
+
+func callStackMethod(err error, name string) []uintptr {
+	method := reflect.ValueOf(err).MethodByName(name)
+	if !method.IsValid() {
+		return nil
+	}
+	t := method.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 ||
+		t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	value := method.Call(nil)[0]
+	if value.Len() == 0 {
+		return nil
+	}
+	pcs := make([]uintptr, value.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(value.Index(i).Uint())
+	}
+	return pcs
+}
+
+// findFormattedErrorStack returns a stack trace in err chain for errors,
+// which expose it only via "%+v" formatting.
+func findFormattedErrorStack(err error) string {
+	var result string
+	for i := 0; err != nil && i < maxErrorChainLength; i++ {
+		switch err.(type) {
+		case errfFormattedError:
+			// errflow error types render errf.Format(...) tree for "%+v".
+		case fmt.Formatter:
+			message := err.Error()
+			formatted := fmt.Sprintf("%+v", err)
+			if strings.HasPrefix(formatted, message) {
+				if stack := strings.TrimSpace(formatted[len(message):]); stack != "" {
+					result = stack
+				}
+			}
+		}
+		err = nextInErrorChain(err)
+	}
+	return result
+}
+
+const errfFnPrefix = "github.com/serhiy-t/errf."
+
+// errflowCaller returns errflow API function called by client code (e.g. "CheckErr", "StdErrflow.CheckInt")
+// and client code frame, which called it.
+func errflowCaller(pcs []uintptr) (api string, caller runtime.Frame) {
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if !isErrflowFrame(frame) {
+			return api, frame
+		}
+		if strings.HasPrefix(frame.Function, errfFnPrefix) {
+			api = strings.TrimPrefix(frame.Function, errfFnPrefix)
+		}
+		if !more {
+			return api, runtime.Frame{}
+		}
+	}
+}
+
+// callerFunction returns name of the function, which called errflow.
+func callerFunction() string {
+	var pcs [maxCallerStackDepth]uintptr
+	_, frame := errflowCaller(pcs[:runtime.Callers(2, pcs[:])])
+	return rangeFuncBodySuffix.ReplaceAllString(frame.Function, "")
+}
+
+func isErrflowFrame(frame runtime.Frame) bool {
+	if strings.HasPrefix(frame.Function, "runtime.") {
+		return true
+	}
+	return strings.HasPrefix(frame.Function, errfFnPrefix) && !strings.HasSuffix(frame.File, "_test.go")
 }
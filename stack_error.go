@@ -0,0 +1,72 @@
+package errf
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// WrapperAddStack is a Wrapper that wraps errors into *StackError,
+// which captures a stack trace of a failed Check* function call.
+//
+// Errors, which already carry a stack trace (captured by errflow or by libraries
+// like github.com/pkg/errors), are returned unmodified.
+//
+// Example:
+//  func example() (err error) {
+//  	defer errf.IfError().Apply(errf.WrapperAddStack).ThenAssignTo(&err)
+//
+//  	// business logic ...
+//  }
+//
+//  if err := example(); err != nil {
+//  	log.Printf("%+v", err)
+//  }
+func WrapperAddStack(ef *Errflow) *Errflow {
+	return Wrapper(addStack)(ef)
+}
+
+func addStack(err error) error {
+	if findErrorStack(err) != nil {
+		return err
+	}
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return &StackError{Err: err, pcs: append([]uintptr{}, pcs[:n]...)}
+}
+
+// StackError is an error with a stack trace.
+//
+// It exposes the same interfaces as github.com/pkg/errors errors:
+// Cause(), StackTrace() and "%+v" formatting.
+//
+// Should be created only via WrapperAddStack option.
+type StackError struct {
+	Err error
+	pcs []uintptr
+}
+
+func (e *StackError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns original error.
+func (e *StackError) Unwrap() error {
+	return e.Err
+}
+
+// Cause returns original error.
+func (e *StackError) Cause() error {
+	return e.Err
+}
+
+// StackTrace returns program counters of the stack trace (same as runtime.Callers).
+func (e *StackError) StackTrace() []uintptr {
+	return e.pcs
+}
+
+// Format implements fmt.Formatter.
+func (e *StackError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*StackError) errfFormatted() {}
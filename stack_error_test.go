@@ -0,0 +1,151 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pkgErrorsFrame and pkgErrorsStackTrace mimic github.com/pkg/errors types.
+type pkgErrorsFrame uintptr
+
+type pkgErrorsStackTrace []pkgErrorsFrame
+
+type pkgErrorsError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newPkgErrorsError(msg string) error {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return &pkgErrorsError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *pkgErrorsError) Error() string { return e.msg }
+
+func (e *pkgErrorsError) StackTrace() pkgErrorsStackTrace {
+	var result pkgErrorsStackTrace
+	for _, pc := range e.stack {
+		result = append(result, pkgErrorsFrame(pc))
+	}
+	return result
+}
+
+type pkgErrorsWithMessage struct {
+	cause error
+	msg   string
+}
+
+func (e *pkgErrorsWithMessage) Error() string { return e.msg + ": " + e.cause.Error() }
+func (e *pkgErrorsWithMessage) Cause() error  { return e.cause }
+
+type formatOnlyError struct{}
+
+func (e formatOnlyError) Error() string { return "format only" }
+
+func (e formatOnlyError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = io.WriteString(s, "format only\ncustom stack line")
+		return
+	}
+	_, _ = io.WriteString(s, e.Error())
+}
+
+func Test_findErrorStack(t *testing.T) {
+	err := &pkgErrorsWithMessage{cause: newPkgErrorsError("error"), msg: "message"}
+
+	pcs := findErrorStack(err)
+	assert.NotNil(t, pcs)
+	assert.Contains(t, parsePCs(pcs).items[0].fn, "errf.Test_findErrorStack")
+
+	assert.Nil(t, findErrorStack(fmt.Errorf("error")))
+	assert.Nil(t, findErrorStack(nil))
+}
+
+func Test_LogMessageStack_ReusesErrorStack(t *testing.T) {
+	var stacks []string
+	defer SetLogFn(func(logMessage *LogMessage) {
+		stacks = append(stacks, logMessage.Stack())
+	}).ThenRestore()
+
+	fn := func(err error) (resultErr error) {
+		defer IfError().LogAlways().ThenAssignTo(&resultErr)
+		CheckErr(err)
+		return nil
+	}
+
+	pkgErr := fmt.Errorf("wrapped: %w", newPkgErrorsError("error"))
+	_ = fn(pkgErr)
+	_ = fn(formatOnlyError{})
+
+	assert.Equal(t, 2, len(stacks))
+	lines := strings.Split(stacks[0], "\n")
+	assert.Equal(t, "error stack trace:", lines[0])
+	assert.Contains(t, lines[1], "errf.Test_LogMessageStack_ReusesErrorStack")
+	assert.Equal(t, "custom stack line", stacks[1])
+}
+
+type countingFormatError struct {
+	formatCalls *int
+}
+
+func (e countingFormatError) Error() string { return "counting" }
+
+func (e countingFormatError) Format(s fmt.State, verb rune) {
+	*e.formatCalls++
+	_, _ = io.WriteString(s, e.Error())
+}
+
+func Test_LogMessageStack_Lazy(t *testing.T) {
+	var messages []*LogMessage
+	defer SetLogFn(func(logMessage *LogMessage) {
+		messages = append(messages, logMessage)
+	}).ThenRestore()
+
+	fn := func(err error) (resultErr error) {
+		defer IfError().LogAlways().ThenAssignTo(&resultErr)
+		CheckErr(err)
+		return nil
+	}
+
+	formatCalls := 0
+	_ = fn(countingFormatError{formatCalls: &formatCalls})
+
+	assert.Equal(t, 1, len(messages))
+	assert.Equal(t, 0, formatCalls)
+	assert.Contains(t, messages[0].Stack(), "errf.Test_LogMessageStack_Lazy")
+	assert.Equal(t, 1, formatCalls)
+}
+
+func Test_WrapperAddStack(t *testing.T) {
+	fn := func(err error) (resultErr error) {
+		defer IfError().Apply(WrapperAddStack).ThenAssignTo(&resultErr)
+		CheckErr(err)
+		return nil
+	}
+
+	err := fn(errTestErrorInstance)
+	assert.EqualError(t, err, "test error")
+	assert.True(t, errors.Is(err, errTestErrorInstance))
+
+	var stackErr *StackError
+	assert.True(t, errors.As(err, &stackErr))
+	assert.Equal(t, errTestErrorInstance, stackErr.Cause())
+	assert.NotNil(t, stackErr.StackTrace())
+	assert.Equal(t, "test error", fmt.Sprintf("%v", err))
+
+	formatted := strings.Split(fmt.Sprintf("%+v", err), "\n")
+	assert.Contains(t, formatted[0], "test error [*errf.StackError at ")
+	assert.Contains(t, formatted[0], "stack_error_test.go")
+	assert.Contains(t, formatted[1], "errf.Test_WrapperAddStack")
+	assert.Equal(t, "  caused by: test error [*errors.errorString]", formatted[len(formatted)-1])
+
+	pkgErr := newPkgErrorsError("error")
+	assert.Equal(t, pkgErr, fn(pkgErr))
+}
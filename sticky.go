@@ -0,0 +1,209 @@
+package errf
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StickyError is an error recorded by StickyWriter or StickyReader.
+// It annotates original error with a byte offset where it happened.
+type StickyError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *StickyError) Error() string {
+	return fmt.Sprintf("at byte offset %d: %s", e.Offset, e.Err.Error())
+}
+
+// Format implements fmt.Formatter.
+func (e *StickyError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*StickyError) errfFormatted() {}
+
+// Unwrap returns original error.
+func (e *StickyError) Unwrap() error {
+	return e.Err
+}
+
+// StickyWriter is an io.Writer, which records first write error
+// and turns all subsequent operations into no-ops (similar to bufio.Writer).
+//
+// Errors are checked once using Check(), typically in a defer statement.
+//
+// Should be created only via errf.Io.StickyWriter(...) method.
+type StickyWriter struct {
+	errflow *Errflow
+	writer  io.Writer
+	offset  int64
+	err     error
+}
+
+// StickyWriter creates StickyWriter for writer.
+//
+// Example:
+//  func writeReport(writer io.Writer, items []Item) (err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	w := errf.Io.StickyWriter(writer)
+//  	defer w.Check()
+//
+//  	for _, item := range items {
+//  		w.Printf("%s: %d\n", item.Name, item.Count)
+//  	}
+//  	return nil
+//  }
+func (ef IoErrflow) StickyWriter(writer io.Writer) *StickyWriter {
+	return &StickyWriter{errflow: ef.errflow, writer: writer}
+}
+
+// Write implements io.Writer.
+// After the first error, it doesn't write anything and returns the same error.
+func (w *StickyWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	n, err := w.writer.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		w.err = &StickyError{Offset: w.offset + int64(n), Err: err}
+	}
+	w.offset += int64(n)
+	return n, w.err
+}
+
+// WriteString writes s.
+func (w *StickyWriter) WriteString(s string) {
+	_, _ = io.WriteString(w, s)
+}
+
+// Printf writes formatted string using fmt.Fprintf.
+func (w *StickyWriter) Printf(format string, a ...interface{}) {
+	if w.err == nil {
+		_, _ = fmt.Fprintf(w, format, a...)
+	}
+}
+
+// WriteBinary writes binary representation of data using binary.Write.
+func (w *StickyWriter) WriteBinary(order binary.ByteOrder, data interface{}) {
+	if w.err == nil {
+		err := binary.Write(w, order, data)
+		if err != nil && w.err == nil {
+			w.err = &StickyError{Offset: w.offset, Err: err}
+		}
+	}
+}
+
+// Offset returns number of bytes successfully written.
+func (w *StickyWriter) Offset() int64 {
+	return w.offset
+}
+
+// Err returns recorded error (as *StickyError), if any.
+func (w *StickyWriter) Err() error {
+	return w.err
+}
+
+// Check sends recorded error to IfError() handler for processing, if there is an error.
+// Useful in defer statements:
+//  w := errf.Io.StickyWriter(writer)
+//  defer w.Check()
+func (w *StickyWriter) Check() CheckResult {
+	return w.errflow.ImplementCheck(recover(), w.err)
+}
+
+// StickyReader is an io.Reader, which records first read error
+// and turns all subsequent operations into no-ops.
+//
+// io.EOF returned from Read is not considered an error, but io.EOF
+// in ReadFull or ReadBinary is (since data is expected to be present).
+//
+// Errors are checked once using Check(), typically in a defer statement.
+//
+// Should be created only via errf.Io.StickyReader(...) method.
+type StickyReader struct {
+	errflow *Errflow
+	reader  io.Reader
+	offset  int64
+	err     error
+}
+
+// StickyReader creates StickyReader for reader.
+//
+// Example:
+//  func readHeader(reader io.Reader) (header Header, err error) {
+//  	defer errf.IfError().ThenAssignTo(&err)
+//
+//  	r := errf.Io.StickyReader(reader)
+//  	defer r.Check()
+//
+//  	r.ReadBinary(binary.LittleEndian, &header.Magic)
+//  	r.ReadBinary(binary.LittleEndian, &header.Version)
+//  	return header, nil
+//  }
+func (ef IoErrflow) StickyReader(reader io.Reader) *StickyReader {
+	return &StickyReader{errflow: ef.errflow, reader: reader}
+}
+
+// Read implements io.Reader.
+// After the first error, it doesn't read anything and returns the same error.
+func (r *StickyReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.reader.Read(p)
+	r.offset += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) {
+		r.err = &StickyError{Offset: r.offset, Err: err}
+		return n, r.err
+	}
+	return n, err
+}
+
+// ReadFull reads exactly len(p) bytes into p using io.ReadFull.
+func (r *StickyReader) ReadFull(p []byte) {
+	if r.err == nil {
+		_, err := io.ReadFull(r, p)
+		r.recordErr(err)
+	}
+}
+
+// ReadBinary reads binary representation of data using binary.Read.
+func (r *StickyReader) ReadBinary(order binary.ByteOrder, data interface{}) {
+	if r.err == nil {
+		r.recordErr(binary.Read(r, order, data))
+	}
+}
+
+func (r *StickyReader) recordErr(err error) {
+	if err != nil && r.err == nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		r.err = &StickyError{Offset: r.offset, Err: err}
+	}
+}
+
+// Offset returns number of bytes successfully read.
+func (r *StickyReader) Offset() int64 {
+	return r.offset
+}
+
+// Err returns recorded error (as *StickyError), if any.
+func (r *StickyReader) Err() error {
+	return r.err
+}
+
+// Check sends recorded error to IfError() handler for processing, if there is an error.
+// Useful in defer statements:
+//  r := errf.Io.StickyReader(reader)
+//  defer r.Check()
+func (r *StickyReader) Check() CheckResult {
+	return r.errflow.ImplementCheck(recover(), r.err)
+}
@@ -0,0 +1,131 @@
+package errf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type limitedWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if w.buf.Len()+len(p) > w.limit {
+		n, _ := w.buf.Write(p[:w.limit-w.buf.Len()])
+		return n, fmt.Errorf("no space left")
+	}
+	return w.buf.Write(p)
+}
+
+func Test_StickyWriter(t *testing.T) {
+	fn := func(writer io.Writer) (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		w := Io.StickyWriter(writer)
+		defer w.Check()
+
+		w.Printf("%s=%d\n", "a", 1)
+		w.WriteString("b=2\n")
+		w.WriteBinary(binary.BigEndian, uint16(0x4142))
+		return nil
+	}
+
+	writer := &limitedWriter{limit: 100}
+	assert.NoError(t, fn(writer))
+	assert.Equal(t, "a=1\nb=2\nAB", writer.buf.String())
+
+	writer = &limitedWriter{limit: 6}
+	err := fn(writer)
+	assert.EqualError(t, err, "at byte offset 6: no space left")
+	assert.Equal(t, "a=1\nb=", writer.buf.String())
+	var stickyErr *StickyError
+	assert.True(t, errors.As(err, &stickyErr))
+	assert.Equal(t, int64(6), stickyErr.Offset)
+}
+
+func Test_StickyWriter_NoopAfterError(t *testing.T) {
+	writer := &limitedWriter{limit: 2}
+	w := Io.StickyWriter(writer)
+
+	n, err := w.Write([]byte("abc"))
+	assert.Equal(t, 2, n)
+	assert.EqualError(t, err, "at byte offset 2: no space left")
+
+	writer.limit = 100
+	n, err = w.Write([]byte("def"))
+	assert.Equal(t, 0, n)
+	assert.Same(t, w.Err(), err)
+	w.Printf("ghi")
+	assert.Equal(t, "ab", writer.buf.String())
+	assert.Equal(t, int64(2), w.Offset())
+}
+
+func Test_StickyWriter_With(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		w := Io.With(WrapperFmtErrorw("wrapped")).StickyWriter(&limitedWriter{})
+		defer w.Check()
+
+		w.WriteString("a")
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: at byte offset 0: no space left")
+}
+
+func Test_StickyReader(t *testing.T) {
+	fn := func(reader io.Reader) (a uint16, b []byte, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		r := Io.StickyReader(reader)
+		defer r.Check()
+
+		r.ReadBinary(binary.BigEndian, &a)
+		b = make([]byte, 3)
+		r.ReadFull(b)
+		return a, b, nil
+	}
+
+	a, b, err := fn(strings.NewReader("ABcde"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x4142), a)
+	assert.Equal(t, []byte("cde"), b)
+
+	_, _, err = fn(strings.NewReader("ABcd"))
+	assert.EqualError(t, err, "at byte offset 4: unexpected EOF")
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	_, _, err = fn(strings.NewReader(""))
+	assert.EqualError(t, err, "at byte offset 0: unexpected EOF")
+
+	_, _, err = fn(io.MultiReader(strings.NewReader("AB"), errReader{}))
+	assert.EqualError(t, err, "at byte offset 2: read error")
+}
+
+func Test_StickyReader_ReadAll(t *testing.T) {
+	fn := func(reader io.Reader) (data []byte, err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		r := Io.StickyReader(reader)
+		defer r.Check()
+
+		data, _ = io.ReadAll(r)
+		return data, nil
+	}
+
+	data, err := fn(strings.NewReader("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), data)
+
+	_, err = fn(io.MultiReader(strings.NewReader("abc"), errReader{}))
+	assert.EqualError(t, err, "at byte offset 3: read error")
+}
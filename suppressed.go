@@ -0,0 +1,82 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AttachSuppressed configures Errflow instance to attach suppressed errors
+// to the resulting error (similar to suppressed exceptions in Java).
+//
+// Errors are considered suppressed when they are not present in resulting error value
+// (e.g. errors after the first one with ReturnStrategyFirst).
+// Suppressed errors can be retrieved using errf.Suppressed(err) function.
+//
+// Resulting error message is unmodified, and errors.Is/errors.As work for
+// the primary error, but "%+v" formatting (see errf.Format) lists suppressed errors.
+//
+// Example:
+//  func writeFile(filename string, data []byte) (err error) {
+//  	defer errf.IfError().AttachSuppressed().ThenAssignTo(&err)
+//
+//  	writer := errf.Io.CheckWriteCloser(os.Create(filename))
+//  	defer errf.CheckDeferErr(writer.Close)
+//
+//  	return errf.CheckDiscard(writer.Write(data)).IfOkReturnNil
+//  }
+//
+//  if err := writeFile(filename, data); err != nil {
+//  	log.Printf("%+v", err)
+//  }
+func AttachSuppressed(ef *Errflow) *Errflow {
+	newEf := ef.copy()
+	newEf.attachSuppressed = true
+	return newEf
+}
+
+// SuppressedError is an error with attached suppressed errors.
+//
+// Should be created only via AttachSuppressed() option.
+type SuppressedError struct {
+	Err        error
+	Suppressed []error
+}
+
+func (e *SuppressedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns primary error.
+func (e *SuppressedError) Unwrap() error {
+	return e.Err
+}
+
+// Format implements fmt.Formatter.
+func (e *SuppressedError) Format(s fmt.State, verb rune) {
+	formatError(e, s, verb)
+}
+
+func (*SuppressedError) errfFormatted() {}
+
+// Suppressed returns suppressed errors attached to err.
+// Returns nil if there are none.
+func Suppressed(err error) []error {
+	var suppressedErr *SuppressedError
+	if errors.As(err, &suppressedErr) {
+		return suppressedErr.Suppressed
+	}
+	return nil
+}
+
+func attachSuppressed(err error, suppressed []error) error {
+	if err == nil || len(suppressed) == 0 {
+		return err
+	}
+	if suppressedErr, ok := err.(*SuppressedError); ok {
+		var allSuppressed []error
+		allSuppressed = append(allSuppressed, suppressedErr.Suppressed...)
+		allSuppressed = append(allSuppressed, suppressed...)
+		return &SuppressedError{Err: suppressedErr.Err, Suppressed: allSuppressed}
+	}
+	return &SuppressedError{Err: err, Suppressed: suppressed}
+}
@@ -0,0 +1,89 @@
+package errf
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AttachSuppressed(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().AttachSuppressed().ThenAssignTo(&err)
+
+		defer CheckDeferErr(errorFn("close error 2"))
+		defer CheckDeferErr(errorFn("close error 1"))
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "test error")
+	assert.True(t, errors.Is(err, errTestErrorInstance))
+	assert.Equal(t, 2, len(Suppressed(err)))
+	assert.EqualError(t, Suppressed(err)[0], "close error 1")
+	assert.EqualError(t, Suppressed(err)[1], "close error 2")
+	assert.Equal(t, "test error", fmt.Sprintf("%v", err))
+	assert.Equal(t, "test error [*errf.SuppressedError]\n"+
+		"  suppressed: close error 1 [*errors.errorString]\n"+
+		"  suppressed: close error 2 [*errors.errorString]\n"+
+		"  caused by: test error [*errors.errorString]", fmt.Sprintf("%+v", err))
+}
+
+func Test_AttachSuppressed_ReturnLast(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnLast().AttachSuppressed().ThenAssignTo(&err)
+
+		defer CheckDeferErr(errorFn("close error"))
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "close error")
+	assert.Equal(t, []error{errTestErrorInstance}, Suppressed(err))
+}
+
+func Test_AttachSuppressed_NoSuppressedErrors(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnCombined().AttachSuppressed().ThenAssignTo(&err)
+
+		defer CheckDeferErr(errorFn("close error"))
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "combined error {test error; close error}")
+	assert.Nil(t, Suppressed(err))
+
+	assert.Nil(t, Suppressed(nil))
+	assert.Nil(t, Suppressed(errTestErrorInstance))
+}
+
+func Test_AttachSuppressed_Disabled(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		defer CheckDeferErr(errorFn("close error"))
+		CheckErr(errTestErrorInstance)
+		return nil
+	}
+
+	err := fn()
+	assert.Equal(t, errTestErrorInstance, err)
+}
+
+func Test_AttachSuppressed_ExternalError(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().AttachSuppressed().ThenAssignTo(&err)
+
+		defer CheckDeferErr(errorFn("close error"))
+		return errTestErrorInstance
+	}
+
+	err := fn()
+	assert.EqualError(t, err, "close error")
+	assert.Equal(t, []error{errTestErrorInstance}, Suppressed(err))
+}
@@ -0,0 +1,263 @@
+package errf
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// TraceEventKind is a kind of errflow trace event.
+type TraceEventKind string
+
+const (
+	// TraceEnter is recorded when IfError() handler is created.
+	TraceEnter = TraceEventKind("enter")
+	// TraceCheck is recorded for every Check* call (both successful and failed).
+	TraceCheck = TraceEventKind("check")
+	// TraceWrap is recorded when wrapper or label is applied to an error.
+	TraceWrap = TraceEventKind("wrap")
+	// TraceCombine is recorded when two errors are combined using return strategy.
+	TraceCombine = TraceEventKind("combine")
+	// TraceHandle is recorded when Handle() callback is called.
+	TraceHandle = TraceEventKind("handle")
+	// TraceAssign is recorded when ThenAssignTo replaces error returned directly from a function.
+	TraceAssign = TraceEventKind("assign")
+	// TraceLeave is recorded when IfError() handler scope ends.
+	TraceLeave = TraceEventKind("leave")
+)
+
+// TraceEvent is a single event of errflow trace.
+type TraceEvent struct {
+	// Kind is a kind of event.
+	Kind TraceEventKind
+	// Location is a source code location of the event (e.g. "file.go:12"), if known.
+	Location string
+	// Message describes the event.
+	Message string
+	// Err is an error, produced by the event (e.g. checked error, wrapped error
+	// or combined error), or nil.
+	Err error
+}
+
+func (e TraceEvent) String() string {
+	var buffer strings.Builder
+	buffer.WriteString(string(e.Kind))
+	if e.Location != "" {
+		buffer.WriteString(" " + e.Location)
+	}
+	if e.Message != "" {
+		buffer.WriteString(": " + e.Message)
+	}
+	return buffer.String()
+}
+
+// Trace is an event log of a single IfError() handler scope.
+type Trace struct {
+	// Function is a name of a function, which created IfError() handler.
+	Function string
+	// Events contains events in the order they were recorded.
+	Events []TraceEvent
+}
+
+func (t *Trace) String() string {
+	lines := []string{"errflow trace: " + t.Function}
+	for _, event := range t.Events {
+		lines = append(lines, "  "+event.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *Trace) add(kind TraceEventKind, location string, err error, format string, a ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.Events = append(t.Events, TraceEvent{
+		Kind:     kind,
+		Location: location,
+		Message:  fmt.Sprintf(format, a...),
+		Err:      err,
+	})
+}
+
+// Tracer receives errflow traces.
+// It is called once for every IfError() handler scope, when the scope ends.
+type Tracer func(trace *Trace)
+
+var globalTracer Tracer
+
+func init() {
+	if value := os.Getenv("ERRF_TRACE"); value != "" && value != "0" {
+		globalTracer = LogTracer
+	}
+}
+
+type tracerRestorer struct {
+	oldTracer Tracer
+}
+
+func (tr *tracerRestorer) ThenRestore() {
+	globalTracer = tr.oldTracer
+}
+
+// SetTracer enables errflow tracing: every IfError() handler scope records
+// an event log (Check* calls, wrappers, return strategy decisions, Handle() callbacks),
+// which is sent to tracer when the scope ends.
+//
+// Tracing is disabled by default. It can be enabled without code changes
+// by setting ERRF_TRACE environment variable, which uses LogTracer.
+// SetTracer(nil) disables tracing.
+//
+// It returns errf.DeferRestorer instance, which can be used to restore previous tracer.
+//
+// Example:
+//  recorder := &errf.TraceRecorder{}
+//  defer errf.SetTracer(recorder.Record).ThenRestore()
+//
+//  err := functionUnderTest()
+//  for _, trace := range recorder.Traces() {
+//  	t.Log(trace)
+//  }
+func SetTracer(tracer Tracer) DeferRestorer {
+	oldTracer := globalTracer
+	globalTracer = tracer
+	return &tracerRestorer{
+		oldTracer: oldTracer,
+	}
+}
+
+// LogTracer logs traces using errflow log function (see SetLogFn).
+func LogTracer(trace *Trace) {
+	globalLogFn(&LogMessage{
+		Format: "%s",
+		A:      []interface{}{trace.String()},
+		Tags:   []string{"errorflow", "trace"},
+	})
+}
+
+// TraceRecorder collects traces, e.g. for inspecting them in tests.
+//
+// Use TraceRecorder.Record as a Tracer.
+type TraceRecorder struct {
+	mu     sync.Mutex
+	traces []*Trace
+}
+
+// Record is a Tracer, which stores trace in the recorder.
+func (r *TraceRecorder) Record(trace *Trace) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, trace)
+}
+
+// Traces returns recorded traces in the order scopes ended
+// (i.e. inner scopes go before outer ones).
+func (r *TraceRecorder) Traces() []*Trace {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Trace(nil), r.traces...)
+}
+
+var traceScopes = newGoroutineScopes[*Trace]()
+
+// enterTrace creates a trace for a new IfError() handler scope.
+// If fn is empty, function which called errflow is used.
+func enterTrace(fn string) *Trace {
+	_, caller, location := traceCaller()
+	if fn == "" {
+		fn = caller
+	}
+	trace := &Trace{Function: fn}
+	trace.add(TraceEnter, location, nil, "IfError()")
+	traceScopes.push(trace)
+	return trace
+}
+
+// currentTrace returns trace of the innermost IfError() handler scope in the current goroutine,
+// or nil if tracing is disabled.
+func currentTrace() *Trace {
+	if globalTracer == nil {
+		return nil
+	}
+	trace, _ := traceScopes.top()
+	return trace
+}
+
+func finishTrace(trace *Trace) {
+	if tracer := globalTracer; tracer != nil {
+		tracer(trace)
+	}
+}
+
+// traceCheck records Check* call result in the current trace.
+func traceCheck(err error) {
+	trace := currentTrace()
+	if trace == nil {
+		return
+	}
+	api, _, location := traceCaller()
+	if err == nil {
+		trace.add(TraceCheck, location, nil, "%s: ok", api)
+	} else {
+		trace.add(TraceCheck, location, err, "%s: error %s", api, errString(err))
+	}
+}
+
+// traceHandle records Handle() callback call in the current trace.
+// It should be called directly from InterimHandler.handle.
+func traceHandle(err error) {
+	trace := currentTrace()
+	if trace == nil {
+		return
+	}
+	handler := "Handle()"
+	if pc, _, _, ok := runtime.Caller(2); ok {
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			name := fn.Name()
+			handler += name[strings.LastIndex(name, "."):]
+		}
+	}
+	_, _, location := traceCaller()
+	switch {
+	case IsPanic(err):
+		trace.add(TraceHandle, location, err, "%s: %s", handler, err.Error())
+	case err != nil:
+		trace.add(TraceHandle, location, err, "%s: error %s", handler, errString(err))
+	default:
+		trace.add(TraceHandle, location, nil, "%s: success", handler)
+	}
+}
+
+// traceCaller returns errflow API function called by client code (e.g. "CheckErr", "StdErrflow.CheckInt"),
+// name of the client function and source location of the call.
+func traceCaller() (api string, fn string, location string) {
+	var pcs [maxCallerStackDepth]uintptr
+	api, frame := errflowCaller(pcs[:runtime.Callers(2, pcs[:])])
+	if frame.Function == "" {
+		return api, "<unknown>", ""
+	}
+	return api, frame.Function, shortFileName(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+func shortFileName(file string) string {
+	if idx := strings.LastIndex(file, "/"); idx != -1 {
+		return file[idx+1:]
+	}
+	return file
+}
+
+func returnStrategyName(rs returnStrategy) string {
+	switch rs {
+	case returnStrategyDefault, returnStrategyFirst:
+		return "ReturnStrategyFirst"
+	case returnStrategyLast:
+		return "ReturnStrategyLast"
+	case returnStrategyWrapped:
+		return "ReturnStrategyWrapped"
+	case returnStrategyCombined:
+		return "ReturnStrategyCombined"
+	}
+	return fmt.Sprintf("returnStrategy(%d)", rs)
+}
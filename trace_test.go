@@ -0,0 +1,145 @@
+package errf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func traceMessages(trace *Trace) []string {
+	var result []string
+	for _, event := range trace.Events {
+		result = append(result, string(event.Kind)+": "+event.Message)
+	}
+	return result
+}
+
+func Test_Trace(t *testing.T) {
+	recorder := &TraceRecorder{}
+	defer SetTracer(recorder.Record).ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().ReturnWrapped().Apply(WrapperFmtErrorw("wrapped")).ThenAssignTo(&err)
+		defer Handle().OnErr(func(err error) {})
+		defer CheckErr(fmt.Errorf("error2"))
+
+		CheckErr(nil)
+		CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: error1 (also: wrapped: error2)")
+	traces := recorder.Traces()
+	if !assert.Len(t, traces, 1) {
+		return
+	}
+	assert.Equal(t, "github.com/serhiy-t/errf.Test_Trace.func1", traces[0].Function)
+	assert.Equal(t, []string{
+		"enter: IfError()",
+		"check: CheckErr: ok",
+		"check: CheckErr: error \"error1\"",
+		"check: CheckErr: error \"error2\"",
+		"handle: Handle().OnErr: error \"error1\"",
+		"wrap: wrapper: \"error1\" -> \"wrapped: error1\"",
+		"wrap: wrapper: \"error2\" -> \"wrapped: error2\"",
+		"combine: ReturnStrategyWrapped(\"wrapped: error1\", \"wrapped: error2\") = " +
+			"\"wrapped: error1 (also: wrapped: error2)\", suppressed: false, false",
+		"leave: error \"wrapped: error1 (also: wrapped: error2)\"",
+	}, traceMessages(traces[0]))
+	assert.True(t, strings.HasPrefix(traces[0].Events[1].Location, "trace_test.go:"))
+	assert.EqualError(t, traces[0].Events[2].Err, "error1")
+	assert.EqualError(t, traces[0].Events[8].Err, "wrapped: error1 (also: wrapped: error2)")
+	assert.Empty(t, traceScopes.byGoroutine)
+}
+
+func Test_Trace_nestedScopes(t *testing.T) {
+	recorder := &TraceRecorder{}
+	defer SetTracer(recorder.Record).ThenRestore()
+
+	inner := func() (err error) {
+		defer IfError().Apply(Label("inner")).ThenAssignTo(&err)
+
+		Std.CheckInt(1, nil)
+		return nil
+	}
+	outer := func() (err error) {
+		defer IfError().ReturnLast().ThenAssignTo(&err)
+
+		CheckErr(inner())
+		defer CheckErr(fmt.Errorf("error2"))
+		return fmt.Errorf("direct")
+	}
+
+	assert.EqualError(t, outer(), "error2")
+	traces := recorder.Traces()
+	if !assert.Len(t, traces, 2) {
+		return
+	}
+	assert.Equal(t, []string{
+		"enter: IfError()",
+		"check: StdErrflow.CheckInt: ok",
+		"leave: success",
+	}, traceMessages(traces[0]))
+	assert.Equal(t, []string{
+		"enter: IfError()",
+		"check: CheckErr: ok",
+		"check: CheckErr: error \"error2\"",
+		"assign: error \"error2\" replaces directly returned error \"direct\"",
+		"leave: error \"error2\"",
+	}, traceMessages(traces[1]))
+}
+
+func Test_Trace_callback(t *testing.T) {
+	recorder := &TraceRecorder{}
+	defer SetTracer(recorder.Record).ThenRestore()
+
+	callbackBody := func() {
+		CheckErr(fmt.Errorf("error1"))
+	}
+	assert.EqualError(t, Func0(callbackBody)(), "error1")
+	traces := recorder.Traces()
+	if assert.Len(t, traces, 1) {
+		assert.Equal(t, "github.com/serhiy-t/errf.Test_Trace_callback.func1", traces[0].Function)
+		assert.Equal(t, []string{
+			"enter: IfError()",
+			"check: CheckErr: error \"error1\"",
+			"leave: error \"error1\"",
+		}, traceMessages(traces[0]))
+	}
+}
+
+func Test_Trace_disabled(t *testing.T) {
+	defer SetTracer(nil).ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "error1")
+	assert.Empty(t, traceScopes.byGoroutine)
+}
+
+func Test_LogTracer(t *testing.T) {
+	var messages []string
+	defer SetLogFn(func(logMessage *LogMessage) {
+		messages = append(messages, fmt.Sprintf("%v "+logMessage.Format, logMessage.Tags, logMessage.A[0]))
+	}).ThenRestore()
+	defer SetTracer(LogTracer).ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		return nil
+	}
+
+	assert.NoError(t, fn())
+	if assert.Len(t, messages, 1) {
+		assert.True(t, strings.HasPrefix(messages[0], "[errorflow trace] errflow trace: "), messages[0])
+		assert.Contains(t, messages[0], "\n  leave: success")
+	}
+}
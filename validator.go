@@ -3,6 +3,8 @@ package errf
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
@@ -11,7 +13,7 @@ import (
 var globalErrflowValidator validator = &noopValidator{}
 
 func init() {
-	if strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], ".test.exe") {
+	if validatorEnabled && (strings.HasSuffix(os.Args[0], ".test") || strings.HasSuffix(os.Args[0], ".test.exe")) {
 		SetStackTraceValidator()
 	}
 }
@@ -56,38 +58,73 @@ func SetNoopValidator() DeferRestorer {
 //
 // It returns errf.DeferRestorer instance,
 // which can be used to restore previous validator, if needed.
+//
+// Validator has no effect in binaries built with "errf_novalidator" build tag.
 func SetStackTraceValidator() DeferRestorer {
 	return setValidator(&stackTraceValidator{})
 }
 
+// SetStrictValidator sets a stack-trace based validator for errflow,
+// which also reports conflicting options.
+//
+// Options are applied in order and the first applied strategy (or label) takes precedence,
+// so contradictory options, stacked on the same Errflow instance, are silently ignored:
+//  errf.With(errf.LogStrategyNever).With(errf.LogStrategyAlways) // logs nothing
+//
+// Strict validator panics when such option is ignored. Use errf.Override
+// to replace already applied values intentionally.
+//
+// It returns errf.DeferRestorer instance,
+// which can be used to restore previous validator, if needed.
+//
+// Validator has no effect in binaries built with "errf_novalidator" build tag.
+func SetStrictValidator() DeferRestorer {
+	return setValidator(&stackTraceValidator{strictOptions: true})
+}
+
 type validator interface {
 	enter()
+	enterCallback(fn interface{})
 	leave()
+	leaveCallback()
 	markPanic()
 	validate()
 	custom(func())
+	optionConflict(ignored, applied string)
 }
 
 type noopValidator struct {
 }
 
-func (v *noopValidator) enter()        {}
-func (v *noopValidator) leave()        {}
-func (v *noopValidator) markPanic()    {}
-func (v *noopValidator) validate()     {}
-func (v *noopValidator) custom(func()) {}
+func (v *noopValidator) enter()                     {}
+func (v *noopValidator) enterCallback(interface{})  {}
+func (v *noopValidator) leave()                     {}
+func (v *noopValidator) leaveCallback()             {}
+func (v *noopValidator) markPanic()                 {}
+func (v *noopValidator) validate()                  {}
+func (v *noopValidator) custom(func())              {}
+func (v *noopValidator) optionConflict(_, _ string) {}
 
 type stackTraceValidator struct {
+	strictOptions bool
 }
 
 func (v *stackTraceValidator) enter() {
 	getGoroutineErrflowStack().push()
 }
 
+func (v *stackTraceValidator) enterCallback(fn interface{}) {
+	getGoroutineErrflowStack().pushFn(getFnName(fn))
+}
+
 func (v *stackTraceValidator) leave() {
 	getGoroutineErrflowStack().pop()
 }
 
+func (v *stackTraceValidator) leaveCallback() {
+	getGoroutineErrflowStack().popFn()
+}
+
 func (v *stackTraceValidator) markPanic() {
 	getGoroutineErrflowStack().markPanic = true
 }
@@ -100,6 +137,13 @@ func (v *stackTraceValidator) custom(fn func()) {
 	fn()
 }
 
+func (v *stackTraceValidator) optionConflict(ignored, applied string) {
+	if v.strictOptions {
+		panic(fmt.Errorf("errflow conflicting options: %s is ignored, because %s is already applied"+
+			" (use errf.Override to replace it)", ignored, applied))
+	}
+}
+
 type errflowStack struct {
 	stack     []string
 	markPanic bool
@@ -109,8 +153,16 @@ func (s *errflowStack) push() {
 	s.stack = append(s.stack, getCurrentCallerFn())
 }
 
+func (s *errflowStack) pushFn(fn string) {
+	s.stack = append(s.stack, fn)
+}
+
 func (s *errflowStack) pop() {
 	s.validate()
+	s.popFn()
+}
+
+func (s *errflowStack) popFn() {
 	s.stack = s.stack[:len(s.stack)-1]
 	s.markPanic = false
 	cleanupGoroutineErrflowStack()
@@ -127,18 +179,38 @@ func (s *errflowStack) validate() {
 }
 
 func getCurrentCallerFn() string {
-	parsedStack := getErrorStackTrace()
+	parsedStack := getCallerStackTrace()
 	if len(parsedStack.items) == 0 {
 		return "<unknown>"
 	}
-	fn := parsedStack.items[0].fn
-	pIdx := strings.Index(fn, "(")
-	if pIdx != -1 {
-		fn = fn[:pIdx+1]
+	return normalizeFnName(parsedStack.items[0].fn)
+}
+
+// getFnName returns function name in the same format as getCurrentCallerFn.
+func getFnName(fn interface{}) string {
+	runtimeFn := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if runtimeFn == nil {
+		return "<unknown>"
+	}
+	return normalizeFnName(runtimeFn.Name())
+}
+
+// normalizeFnName converts function name from a stack trace (e.g. "pkg.(*T).M(0xc000010000)")
+// or from runtime (e.g. "pkg.(*T).M-fm" for method values) into "pkg.(*T).M".
+func normalizeFnName(fn string) string {
+	if strings.HasSuffix(fn, ")") {
+		if pIdx := strings.LastIndex(fn, "("); pIdx != -1 {
+			fn = fn[:pIdx]
+		}
 	}
-	return fn
+	fn = strings.TrimSuffix(fn, "-fm")
+	// Range-over-func loop bodies are compiled into closures (e.g. "fn-range1"),
+	// but they belong to the enclosing function.
+	return rangeFuncBodySuffix.ReplaceAllString(fn, "")
 }
 
+var rangeFuncBodySuffix = regexp.MustCompile(`-range[0-9]+`)
+
 func (s *errflowStack) empty() bool {
 	return len(s.stack) == 0
 }
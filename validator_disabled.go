@@ -0,0 +1,7 @@
+//go:build errf_novalidator
+
+package errf
+
+// validatorEnabled is false when built with "errf_novalidator" build tag,
+// which compiles out all validator calls.
+const validatorEnabled = false
@@ -0,0 +1,7 @@
+//go:build !errf_novalidator
+
+package errf
+
+// validatorEnabled is false when built with "errf_novalidator" build tag
+// (see validator_disabled.go).
+const validatorEnabled = true
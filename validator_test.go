@@ -1,3 +1,5 @@
+//go:build !errf_novalidator
+
 package errf
 
 import (
@@ -103,3 +105,57 @@ func TestValidator_MissingCatchStatement(t *testing.T) {
 		_ = fn(5)
 	})
 }
+
+func TestValidator_StrictOptionConflict(t *testing.T) {
+	defer SetStrictValidator().ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().ThenAssignTo(&err)
+
+		return With(LogStrategyNever).With(LogStrategyAlways).CheckErr(fmt.Errorf("error")).IfOkReturnNil
+	}
+	assert.PanicsWithError(t, "errflow conflicting options: LogStrategyAlways is ignored, "+
+		"because LogStrategyNever is already applied (use errf.Override to replace it)", func() {
+		_ = fn()
+	})
+}
+
+func TestValidator_StrictNoConflict(t *testing.T) {
+	defer SetStrictValidator().ThenRestore()
+
+	fn := func() (err error) {
+		defer IfError().ReturnFirst().Apply(Override(Label("outer"))).ThenAssignTo(&err)
+
+		return With(ReturnStrategyFirst, Label("inner")).CheckErr(fmt.Errorf("error")).IfOkReturnNil
+	}
+	assert.NotPanics(t, func() {
+		err := fn()
+		assert.EqualError(t, err, "error")
+		assert.Equal(t, "outer", LabelOf(err))
+	})
+}
+
+func TestValidator_DefaultIgnoresOptionConflict(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnLast().ThenAssignTo(&err)
+
+		defer With(ReturnStrategyFirst).CheckErr(fmt.Errorf("error2"))
+		return CheckErr(fmt.Errorf("error1")).IfOkReturnNil
+	}
+	assert.NotPanics(t, func() {
+		assert.EqualError(t, fn(), "error1")
+	})
+}
+
+func TestValidator_CommaOkChecksWithoutIfError(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 1
+	for name, fn := range map[string]func(){
+		"CheckOk":   func() { CheckOk(1, true, "not found") },
+		"CheckType": func() { CheckType[int](1) },
+		"CheckKey":  func() { CheckKey(map[string]int{"a": 1}, "a") },
+		"CheckRecv": func() { CheckRecv[int](ch) },
+	} {
+		assert.PanicsWithError(t, "errflow incorrect call sequence", fn, name)
+	}
+}
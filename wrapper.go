@@ -40,12 +40,15 @@ func Wrapper(wrapper func(err error) error) ErrflowOption {
 
 func fmtErrorf(format string, a ...interface{}) func(err error) error {
 	return func(err error) error {
+		args := make([]interface{}, len(a))
 		for i, v := range a {
 			if v == OriginalErr {
-				a[i] = err
+				args[i] = err
+			} else {
+				args[i] = v
 			}
 		}
-		return fmt.Errorf(format, a...)
+		return fmt.Errorf(format, args...)
 	}
 }
 
@@ -81,6 +81,18 @@ func Test_WrapperFmtErrorW(t *testing.T) {
 	assert.EqualError(t, fn(), "wrapped: error1")
 }
 
+func Test_WrapperFmtErrorw_multipleErrors(t *testing.T) {
+	fn := func() (err error) {
+		defer IfError().ReturnWrapped().Apply(WrapperFmtErrorw("wrapped")).ThenAssignTo(&err)
+
+		defer CheckErr(fmt.Errorf("error2"))
+		CheckErr(fmt.Errorf("error1"))
+		return nil
+	}
+
+	assert.EqualError(t, fn(), "wrapped: error1 (also: wrapped: error2)")
+}
+
 func Test_OriginalErr(t *testing.T) {
 	assert.EqualError(t, OriginalErr, "errflow original error placeholder")
 }